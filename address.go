@@ -1,12 +1,18 @@
 package cardano
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"hash/crc32"
 	"math/big"
 
+	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/base58"
 	"github.com/echovl/cardano-go/internal/bech32"
 	"github.com/echovl/cardano-go/internal/cbor"
 	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
 type AddressType byte
@@ -15,6 +21,8 @@ const (
 	Base       AddressType = 0x00
 	Ptr        AddressType = 0x04
 	Enterprise AddressType = 0x06
+	Byron      AddressType = 0x08
+	Reward     AddressType = 0x0E
 )
 
 // Address represents a Cardano address.
@@ -25,19 +33,36 @@ type Address struct {
 
 	Payment StakeCredential
 	Stake   StakeCredential
+
+	// Byron is the payload of a Byron (bootstrap) address.
+	Byron *ByronPayload
 }
 
 // NewAddress creates an Address from a bech32 encoded string.
+// Byron addresses are decoded from base58.
 func NewAddress(bech string) (Address, error) {
 	_, bytes, err := bech32.DecodeToBase256(bech)
 	if err != nil {
-		return Address{}, err
+		// Only valid Byron addresses are accepted as base58
+		b58bytes, b58err := base58.Decode(bech)
+		if b58err != nil {
+			return Address{}, err
+		}
+		addr, byronErr := newByronAddressFromBytes(b58bytes)
+		if byronErr != nil {
+			return Address{}, err
+		}
+		return addr, nil
 	}
 	return NewAddressFromBytes(bytes)
 }
 
 // NewAddressFromBytes creates an Address from bytes.
 func NewAddressFromBytes(bytes []byte) (Address, error) {
+	if len(bytes) == 0 {
+		return Address{}, errors.New("empty address")
+	}
+
 	addr := Address{
 		Type:    AddressType(bytes[0] >> 4),
 		Network: Network(bytes[0] & 0x01),
@@ -160,6 +185,26 @@ func NewAddressFromBytes(bytes []byte) (Address, error) {
 			Type:       ScriptCredential,
 			ScriptHash: bytes[1:29],
 		}
+	case Reward:
+		if len(bytes) != 29 {
+			return addr, errors.New("reward address length should be 29")
+		}
+		addr.Stake = StakeCredential{
+			Type:    KeyCredential,
+			KeyHash: bytes[1:29],
+		}
+	case Reward + 1:
+		if len(bytes) != 29 {
+			return addr, errors.New("reward address length should be 29")
+		}
+		addr.Stake = StakeCredential{
+			Type:       ScriptCredential,
+			ScriptHash: bytes[1:29],
+		}
+	case Byron:
+		return newByronAddressFromBytes(bytes)
+	default:
+		return addr, fmt.Errorf("invalid address type %d", addr.Type)
 	}
 
 	return addr, nil
@@ -167,8 +212,12 @@ func NewAddressFromBytes(bytes []byte) (Address, error) {
 
 // MarshalCBOR implements cbor.Marshaler.
 func (addr *Address) MarshalCBOR() ([]byte, error) {
+	bytes, err := addr.bytes()
+	if err != nil {
+		return nil, err
+	}
 	em, _ := cbor.CanonicalEncOptions().EncMode()
-	return em.Marshal(addr.Bytes())
+	return em.Marshal(bytes)
 }
 
 // UnmarshalCBOR implements cbor.Unmarshaler.
@@ -187,12 +236,26 @@ func (addr *Address) UnmarshalCBOR(data []byte) error {
 	addr.Payment = decoded.Payment
 	addr.Stake = decoded.Stake
 	addr.Pointer = decoded.Pointer
+	addr.Byron = decoded.Byron
 
 	return nil
 }
 
 // Bytes returns the CBOR encoding of the Address as bytes.
+// It returns nil if a Byron address payload can't be encoded.
 func (addr *Address) Bytes() []byte {
+	bytes, err := addr.bytes()
+	if err != nil {
+		return nil
+	}
+	return bytes
+}
+
+func (addr *Address) bytes() ([]byte, error) {
+	if addr.Type == Byron {
+		return addr.Byron.addressBytes()
+	}
+
 	var networkByte uint8
 	switch addr.Network {
 	case Testnet, Preprod:
@@ -208,6 +271,8 @@ func (addr *Address) Bytes() []byte {
 		addrBytes = append(addrBytes, addr.Stake.Hash()...)
 	case Enterprise, Enterprise + 1:
 		addrBytes = append(addrBytes, addr.Payment.Hash()...)
+	case Reward, Reward + 1:
+		addrBytes = append(addrBytes, addr.Stake.Hash()...)
 	case Ptr, Ptr + 1:
 		addrBytes = append(addrBytes, addr.Payment.Hash()...)
 		addrBytes = append(addrBytes, encodeToNat(addr.Pointer.Slot)...)
@@ -215,19 +280,28 @@ func (addr *Address) Bytes() []byte {
 		addrBytes = append(addrBytes, encodeToNat(addr.Pointer.CertIndex)...)
 	}
 
-	return addrBytes
+	return addrBytes, nil
 }
 
 // Bech32 returns the Address encoded as bech32.
+// Byron addresses don't have a bech32 representation and are encoded as base58.
 func (addr *Address) Bech32() string {
-	addrStr, err := bech32.EncodeFromBase256(getHrp(addr.Network), addr.Bytes())
+	if addr.Type == Byron {
+		return addr.Base58()
+	}
+	addrStr, err := bech32.EncodeFromBase256(getHrp(addr.Network, addr.Type), addr.Bytes())
 	if err != nil {
 		panic(err)
 	}
 	return addrStr
 }
 
-// String returns the Address encoded as bech32.
+// Base58 returns the Address encoded as base58, as used by Byron addresses.
+func (addr *Address) Base58() string {
+	return base58.Encode(addr.Bytes())
+}
+
+// String returns the Address encoded as bech32, or base58 for Byron addresses.
 func (addr Address) String() string {
 	return addr.Bech32()
 }
@@ -254,6 +328,262 @@ func NewEnterpriseAddress(network Network, payment StakeCredential) (Address, er
 	return Address{Type: addrType, Network: network, Payment: payment}, nil
 }
 
+// NewRewardAddress returns a new Reward Address.
+func NewRewardAddress(network Network, stake StakeCredential) (Address, error) {
+	addrType := Reward
+	if stake.Type == ScriptCredential {
+		addrType = Reward + 1
+	}
+	return Address{Type: addrType, Network: network, Stake: stake}, nil
+}
+
+// RewardAddress returns the Reward Address associated with the stake credential
+// of a Base Address.
+func (addr *Address) RewardAddress() (Address, error) {
+	switch addr.Type {
+	case Base, Base + 1, Base + 2, Base + 3:
+		return NewRewardAddress(addr.Network, addr.Stake)
+	default:
+		return Address{}, errors.New("reward address can only be derived from a base address")
+	}
+}
+
+// ByronAddressType is the type of the spending data of a Byron address.
+type ByronAddressType uint64
+
+const (
+	ByronPubKeyAddress ByronAddressType = 0
+	ByronScriptAddress ByronAddressType = 1
+	ByronRedeemAddress ByronAddressType = 2
+)
+
+// ByronAddressAttributes are the attributes of a Byron address.
+type ByronAddressAttributes struct {
+	// DerivationPath is the encrypted HD derivation path used by legacy
+	// random wallets, it's nil for Icarus style addresses.
+	DerivationPath []byte
+	// NetworkMagic is the protocol magic of the network, it's nil on mainnet.
+	NetworkMagic *uint32
+}
+
+// ByronPayload is the payload of a Byron address.
+type ByronPayload struct {
+	Root       Hash28
+	Attributes ByronAddressAttributes
+	Type       ByronAddressType
+
+	// original is the encoding of a decoded payload, used to serialize decoded
+	// addresses byte-exactly while they aren't modified.
+	original originalCBOR
+}
+
+// NewByronAddress returns a new Byron public key Address for the given
+// extended public key.
+func NewByronAddress(xpub crypto.XPubKey, attrs ByronAddressAttributes) (Address, error) {
+	payload := &ByronPayload{Attributes: attrs, Type: ByronPubKeyAddress}
+	attrsBytes, err := payload.attributesBytes()
+	if err != nil {
+		return Address{}, err
+	}
+	payload.Root, err = byronAddressRoot(xpub, attrsBytes)
+	if err != nil {
+		return Address{}, err
+	}
+	return Address{
+		Type:    Byron,
+		Network: byronNetwork(attrs.NetworkMagic),
+		Byron:   payload,
+	}, nil
+}
+
+// IsByronKey returns true if the Byron Address was derived from the given
+// extended public key.
+func (addr *Address) IsByronKey(xpub crypto.XPubKey) bool {
+	if addr.Type != Byron || addr.Byron == nil || addr.Byron.Type != ByronPubKeyAddress {
+		return false
+	}
+	attrsBytes, err := addr.Byron.attributesBytes()
+	if err != nil {
+		return false
+	}
+	root, err := byronAddressRoot(xpub, attrsBytes)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(root, addr.Byron.Root)
+}
+
+// byronAddressRoot computes the root of a Byron public key address:
+// blake2b224(sha3_256([type, [0, xpub], attributes])).
+func byronAddressRoot(xpub crypto.XPubKey, attrs []byte) (Hash28, error) {
+	if len(xpub) != 64 {
+		return nil, fmt.Errorf("invalid extended public key length %d", len(xpub))
+	}
+	spendingData := []interface{}{uint64(0), []byte(xpub)}
+	bytes, err := cborEnc.Marshal([]interface{}{ByronPubKeyAddress, spendingData, cbor.RawMessage(attrs)})
+	if err != nil {
+		return nil, err
+	}
+	hash := sha3.Sum256(bytes)
+	return Blake224Hash(hash[:])
+}
+
+func byronNetwork(magic *uint32) Network {
+	switch {
+	case magic == nil:
+		return Mainnet
+	case *magic == 1:
+		return Preprod
+	default:
+		return Testnet
+	}
+}
+
+// cborMap returns the attributes as a CBOR map, values are CBOR encoded
+// inside bytestrings.
+func (attrs *ByronAddressAttributes) cborMap() (map[uint64][]byte, error) {
+	m := map[uint64][]byte{}
+	if attrs.DerivationPath != nil {
+		path, err := cborEnc.Marshal(attrs.DerivationPath)
+		if err != nil {
+			return nil, err
+		}
+		m[1] = path
+	}
+	if attrs.NetworkMagic != nil {
+		magic, err := cborEnc.Marshal(*attrs.NetworkMagic)
+		if err != nil {
+			return nil, err
+		}
+		m[2] = magic
+	}
+	return m, nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The original encoding is used for decoded payloads that weren't modified.
+func (p *ByronPayload) MarshalCBOR() ([]byte, error) {
+	bytes, err := p.marshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	return p.original.bytes(bytes), nil
+}
+
+func (p *ByronPayload) marshalCBOR() ([]byte, error) {
+	attrs, err := p.Attributes.cborMap()
+	if err != nil {
+		return nil, err
+	}
+	return cborEnc.Marshal([]interface{}{p.Root, attrs, p.Type})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (p *ByronPayload) UnmarshalCBOR(data []byte) error {
+	var payload struct {
+		_          struct{} `cbor:",toarray"`
+		Root       []byte
+		Attributes map[uint64][]byte
+		Type       ByronAddressType
+	}
+	if err := cborDec.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	if len(payload.Root) != 28 {
+		return fmt.Errorf("invalid byron address root length %d", len(payload.Root))
+	}
+
+	attrs := ByronAddressAttributes{}
+	if path, ok := payload.Attributes[1]; ok {
+		if err := cborDec.Unmarshal(path, &attrs.DerivationPath); err != nil {
+			return err
+		}
+	}
+	if magicBytes, ok := payload.Attributes[2]; ok {
+		var magic uint32
+		if err := cborDec.Unmarshal(magicBytes, &magic); err != nil {
+			return err
+		}
+		attrs.NetworkMagic = &magic
+	}
+
+	p.Root = payload.Root
+	p.Attributes = attrs
+	p.Type = payload.Type
+
+	encoded, err := p.marshalCBOR()
+	if err != nil {
+		return err
+	}
+	p.original = newOriginalCBOR(data, encoded)
+
+	return nil
+}
+
+// attributesBytes returns the CBOR encoding of the address attributes, as
+// they're encoded in the payload.
+func (p *ByronPayload) attributesBytes() ([]byte, error) {
+	bytes, err := p.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	var payload []cbor.RawMessage
+	if err := cborDec.Unmarshal(bytes, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload) != 3 {
+		return nil, fmt.Errorf("invalid byron address payload, expected 3 elements got %d", len(payload))
+	}
+	return payload[1], nil
+}
+
+// addressBytes returns the Byron address bytes: [tag24(payload), crc32(payload)].
+func (p *ByronPayload) addressBytes() ([]byte, error) {
+	if p == nil {
+		return nil, errors.New("missing byron address payload")
+	}
+	payload, err := p.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	return cborEnc.Marshal([]interface{}{
+		cbor.Tag{Number: 24, Content: payload},
+		crc32.ChecksumIEEE(payload),
+	})
+}
+
+func newByronAddressFromBytes(bytes []byte) (Address, error) {
+	var raw []cbor.RawMessage
+	if err := cborDec.Unmarshal(bytes, &raw); err != nil {
+		return Address{}, fmt.Errorf("invalid byron address: %w", err)
+	}
+	if len(raw) != 2 {
+		return Address{}, fmt.Errorf("invalid byron address, expected 2 elements got %d", len(raw))
+	}
+	payloadBytes, err := unwrapEncodedCBOR(raw[0])
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid byron address: %w", err)
+	}
+	var checksum uint32
+	if err := cborDec.Unmarshal(raw[1], &checksum); err != nil {
+		return Address{}, fmt.Errorf("invalid byron address checksum: %w", err)
+	}
+	if crc := crc32.ChecksumIEEE(payloadBytes); crc != checksum {
+		return Address{}, fmt.Errorf("invalid byron address checksum, got %d want %d", checksum, crc)
+	}
+
+	payload := &ByronPayload{}
+	if err := payload.UnmarshalCBOR(payloadBytes); err != nil {
+		return Address{}, err
+	}
+
+	return Address{
+		Type:    Byron,
+		Network: byronNetwork(payload.Attributes.NetworkMagic),
+		Byron:   payload,
+	}, nil
+}
+
 // Pointer is the location of the Stake Registration Certificate in the blockchain.
 type Pointer struct {
 	Slot      uint64
@@ -316,11 +646,15 @@ func Blake224Hash(b []byte) ([]byte, error) {
 	return hash.Sum(nil), err
 }
 
-func getHrp(network Network) string {
+func getHrp(network Network, addrType AddressType) string {
+	prefix := "addr"
+	if addrType == Reward || addrType == Reward+1 {
+		prefix = "stake"
+	}
 	switch network {
 	case Testnet, Preprod:
-		return "addr_test"
+		return prefix + "_test"
 	default:
-		return "addr"
+		return prefix
 	}
 }
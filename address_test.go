@@ -1,6 +1,8 @@
 package cardano
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 
 	"github.com/echovl/cardano-go/crypto"
@@ -8,17 +10,21 @@ import (
 )
 
 const (
-	paymentKey = "addr_vk1w0l2sr2zgfm26ztc6nl9xy8ghsk5sh6ldwemlpmp9xylzy4dtf7st80zhd"
-	stakeKey   = "stake_vk1px4j0r2fk7ux5p23shz8f3y5y2qam7s954rgf3lg5merqcj6aetsft99wu"
-	scriptHash = "script1cda3khwqv60360rp5m7akt50m6ttapacs8rqhn5w342z7r35m37"
-	addrType0  = "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"
-	addrType1  = "addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh"
-	addrType2  = "addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve"
-	addrType3  = "addr1x8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gt7r0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shskhj42g"
-	addrType4  = "addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k"
-	addrType5  = "addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu"
-	addrType6  = "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"
-	addrType7  = "addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx"
+	paymentKey   = "addr_vk1w0l2sr2zgfm26ztc6nl9xy8ghsk5sh6ldwemlpmp9xylzy4dtf7st80zhd"
+	stakeKey     = "stake_vk1px4j0r2fk7ux5p23shz8f3y5y2qam7s954rgf3lg5merqcj6aetsft99wu"
+	scriptHash   = "script1cda3khwqv60360rp5m7akt50m6ttapacs8rqhn5w342z7r35m37"
+	addrType0    = "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"
+	addrType1    = "addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh"
+	addrType2    = "addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve"
+	addrType3    = "addr1x8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gt7r0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shskhj42g"
+	addrType4    = "addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k"
+	addrType5    = "addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu"
+	addrType6    = "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"
+	addrType7    = "addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx"
+	addrType14   = "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"
+	addrType15   = "stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5"
+	icarusAddr   = "Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi"
+	daedalusAddr = "37btjrVyb4KDXBNC4haBVPCrro8AQPHwvCMp3RFhhSVWwfFmZ6wwzSK6JK1hY6wHNmtrpTf1kdbva8TCneM2YsiXT7mrzT21EacHnPpz5YyUdj64na"
 )
 
 var (
@@ -31,6 +37,10 @@ var (
 		"addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu",
 		"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
 		"addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx",
+		"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw",
+		"stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5",
+		"stake_test1uqehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gssrtvn",
+		"stake_test17rphkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcljw6kf",
 	}
 )
 
@@ -140,6 +150,233 @@ func TestNewAddress(t *testing.T) {
 	if got, want := enterprise1.Bech32(), addrType7; got != want {
 		t.Errorf("invalid enterprise address\ngot: %s\nwant: %s", got, want)
 	}
+
+	reward0, err := NewRewardAddress(Mainnet, stakeAddrCred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reward0.Bech32(), addrType14; got != want {
+		t.Errorf("invalid reward address\ngot: %s\nwant: %s", got, want)
+	}
+
+	reward1, err := NewRewardAddress(Mainnet, scriptCred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reward1.Bech32(), addrType15; got != want {
+		t.Errorf("invalid reward address\ngot: %s\nwant: %s", got, want)
+	}
+}
+
+func TestRewardAddress(t *testing.T) {
+	testcases := []struct {
+		addr    string
+		reward  string
+		wantErr bool
+	}{
+		{addr: addrType0, reward: addrType14},
+		{addr: addrType1, reward: addrType14},
+		{addr: addrType2, reward: addrType15},
+		{addr: addrType3, reward: addrType15},
+		{addr: addrType4, wantErr: true},
+		{addr: addrType6, wantErr: true},
+		{addr: addrType14, wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		addr, err := NewAddress(tc.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reward, err := addr.RewardAddress()
+		if err != nil {
+			if tc.wantErr {
+				continue
+			}
+			t.Fatal(err)
+		}
+		if tc.wantErr {
+			t.Fatalf("expected error for %s", tc.addr)
+		}
+		if got, want := reward.Bech32(), tc.reward; got != want {
+			t.Errorf("invalid reward address\ngot: %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestByronAddress(t *testing.T) {
+	testnetMagic := uint32(1097911063)
+	testcases := []struct {
+		addr    string
+		bytes   string
+		network Network
+		root    string
+		attrs   ByronAddressAttributes
+	}{
+		{
+			addr:    icarusAddr,
+			bytes:   "82d818582183581cba970ad36654d8dd8f74274b733452ddeab9a62a397746be3c42ccdda0001a9026da5b",
+			network: Mainnet,
+			root:    "ba970ad36654d8dd8f74274b733452ddeab9a62a397746be3c42ccdd",
+		},
+		{
+			addr:    daedalusAddr,
+			bytes:   "82d818584983581c7e9ee4a9527dea9091e2d580edd6716888c42f75d96276290f98fe0ba201581e581c0cdf39b531d1ac0963cbd183f63e43d895d16a9c567c95e1056e28bd02451a4170cb17001a53249b67",
+			network: Testnet,
+			root:    "7e9ee4a9527dea9091e2d580edd6716888c42f75d96276290f98fe0b",
+			attrs: ByronAddressAttributes{
+				DerivationPath: []byte{
+					0x0c, 0xdf, 0x39, 0xb5, 0x31, 0xd1, 0xac, 0x09, 0x63, 0xcb, 0xd1, 0x83, 0xf6, 0x3e,
+					0x43, 0xd8, 0x95, 0xd1, 0x6a, 0x9c, 0x56, 0x7c, 0x95, 0xe1, 0x05, 0x6e, 0x28, 0xbd,
+				},
+				NetworkMagic: &testnetMagic,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		addr, err := NewAddress(tc.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.Type != Byron {
+			t.Errorf("invalid address type\ngot: %v\nwant: %v", addr.Type, Byron)
+		}
+		if addr.Network != tc.network {
+			t.Errorf("invalid network\ngot: %v\nwant: %v", addr.Network, tc.network)
+		}
+		if got, want := hex.EncodeToString(addr.Bytes()), tc.bytes; got != want {
+			t.Errorf("invalid address bytes\ngot: %s\nwant: %s", got, want)
+		}
+		if got, want := addr.String(), tc.addr; got != want {
+			t.Errorf("invalid address\ngot: %s\nwant: %s", got, want)
+		}
+		if got, want := hex.EncodeToString(addr.Byron.Root), tc.root; got != want {
+			t.Errorf("invalid root\ngot: %s\nwant: %s", got, want)
+		}
+		if got, want := addr.Byron.Attributes.DerivationPath, tc.attrs.DerivationPath; !bytes.Equal(got, want) {
+			t.Errorf("invalid derivation path\ngot: %x\nwant: %x", got, want)
+		}
+		if tc.attrs.NetworkMagic != nil {
+			if got := addr.Byron.Attributes.NetworkMagic; got == nil || *got != *tc.attrs.NetworkMagic {
+				t.Errorf("invalid network magic\ngot: %v\nwant: %v", got, *tc.attrs.NetworkMagic)
+			}
+		}
+
+		// Encoding from the decoded fields must produce the same address
+		rebuilt := Address{
+			Type: Byron,
+			Byron: &ByronPayload{
+				Root:       addr.Byron.Root,
+				Attributes: addr.Byron.Attributes,
+				Type:       addr.Byron.Type,
+			},
+		}
+		if got, want := rebuilt.String(), tc.addr; got != want {
+			t.Errorf("invalid rebuilt address\ngot: %s\nwant: %s", got, want)
+		}
+
+		// Byron addresses are serialized as raw bytes inside transaction outputs
+		txOut := NewTxOutput(addr, NewValue(1000000))
+		txOutBytes, err := txOut.MarshalCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := TxOutput{}
+		if err := decoded.UnmarshalCBOR(txOutBytes); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := decoded.Address.String(), tc.addr; got != want {
+			t.Errorf("invalid tx output address\ngot: %s\nwant: %s", got, want)
+		}
+	}
+
+	// Invalid checksum
+	invalid, _ := hex.DecodeString("82d818582183581cba970ad36654d8dd8f74274b733452ddeab9a62a397746be3c42ccdda0001a9026da5c")
+	if _, err := NewAddressFromBytes(invalid); err == nil {
+		t.Errorf("expected checksum error")
+	}
+}
+
+func TestModifiedByronAddress(t *testing.T) {
+	addr, err := NewAddress(daedalusAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	magic := uint32(1)
+	addr.Byron.Attributes.NetworkMagic = &magic
+	addr.Byron.Root = make([]byte, 28)
+
+	// Modified payloads are re-encoded with a new checksum
+	decoded, err := NewAddressFromBytes(addr.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Byron.Attributes.NetworkMagic; got == nil || *got != magic {
+		t.Errorf("invalid network magic\ngot: %v\nwant: %v", got, magic)
+	}
+	if got, want := decoded.Byron.Root, addr.Byron.Root; !bytes.Equal(got, want) {
+		t.Errorf("invalid root\ngot: %x\nwant: %x", got, want)
+	}
+	if got, want := decoded.Byron.Attributes.DerivationPath, addr.Byron.Attributes.DerivationPath; !bytes.Equal(got, want) {
+		t.Errorf("invalid derivation path\ngot: %x\nwant: %x", got, want)
+	}
+}
+
+func TestNewByronAddress(t *testing.T) {
+	xprv := crypto.NewXPrvKeyFromEntropy([]byte("byron address entropy"), "")
+	magic := uint32(1)
+	addr, err := NewByronAddress(xprv.XPubKey(), ByronAddressAttributes{NetworkMagic: &magic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.Network != Preprod {
+		t.Errorf("invalid network\ngot: %v\nwant: %v", addr.Network, Preprod)
+	}
+
+	decoded, err := NewAddress(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.IsByronKey(xprv.XPubKey()) {
+		t.Errorf("address should be derived from the key")
+	}
+
+	other := crypto.NewXPrvKeyFromEntropy([]byte("other entropy"), "")
+	if decoded.IsByronKey(other.XPubKey()) {
+		t.Errorf("address should not be derived from the key")
+	}
+}
+
+func TestInvalidAddress(t *testing.T) {
+	testcases := []struct {
+		name string
+		addr string
+	}{
+		{name: "base58 with header type 13", addr: "vwkC7BFEUYdtk3d"},
+		{name: "base58 with header type 12", addr: "r7dJ8NhuVnczsq6"},
+		{name: "invalid bech32", addr: "addr1invalid"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewAddress(tc.addr); err == nil {
+				t.Errorf("expected invalid address error for %s", tc.addr)
+			}
+		})
+	}
+
+	for _, header := range []byte{0x90, 0xa0, 0xb1, 0xc0, 0xd1} {
+		addrBytes := append([]byte{header}, make([]byte, 28)...)
+		if _, err := NewAddressFromBytes(addrBytes); err == nil {
+			t.Errorf("expected invalid address type error for header %x", header)
+		}
+	}
+
+	if _, err := (&Address{Type: Byron}).MarshalCBOR(); err == nil {
+		t.Errorf("expected missing byron payload error")
+	}
 }
 
 func TestNat(t *testing.T) {
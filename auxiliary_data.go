@@ -1,6 +1,8 @@
 package cardano
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/echovl/cardano-go/internal/cbor"
@@ -9,53 +11,150 @@ import (
 // Metadata represents the transaction metadata.
 type Metadata map[uint]interface{}
 
+// AuxiliaryDataFormat is the encoding format of the auxiliary data, which
+// changed across eras.
+type AuxiliaryDataFormat uint8
+
+const (
+	// AlonzoAuxiliaryData is a map tagged with 259, used since Alonzo.
+	AlonzoAuxiliaryData AuxiliaryDataFormat = iota
+	// ShelleyAuxiliaryData is a plain metadata map.
+	ShelleyAuxiliaryData
+	// ShelleyMAAuxiliaryData is a [metadata, native scripts] array, used in Allegra and Mary.
+	ShelleyMAAuxiliaryData
+)
+
 // AuxiliaryData is the auxiliary data in the transaction.
 type AuxiliaryData struct {
-	Metadata      Metadata    `cbor:"0,keyasint,omitempty"`
-	NativeScripts interface{} `cbor:"1,keyasint,omitempty"`
-	PlutusScripts interface{} `cbor:"2,keyasint,omitempty"`
+	Metadata        Metadata         `cbor:"0,keyasint,omitempty"`
+	NativeScripts   []NativeScript   `cbor:"1,keyasint,omitempty"`
+	PlutusV1Scripts []PlutusV1Script `cbor:"2,keyasint,omitempty"`
+	PlutusV2Scripts []PlutusV2Script `cbor:"3,keyasint,omitempty"`
+	PlutusV3Scripts []PlutusV3Script `cbor:"4,keyasint,omitempty"`
+
+	// Format is the encoding format. Decoded auxiliary data keeps the format it was encoded with.
+	Format AuxiliaryDataFormat `cbor:"-"`
+
+	original originalCBOR
+}
+
+type shelleyMAAuxiliaryData struct {
+	_             struct{} `cbor:",toarray"`
+	Metadata      Metadata
+	NativeScripts []NativeScript
 }
 
 // MarshalCBOR implements cbor.Marshaler
 func (d *AuxiliaryData) MarshalCBOR() ([]byte, error) {
-	type auxiliaryData AuxiliaryData
-
-	// Register tag 259 for maps
-	tags, err := d.tagSet(auxiliaryData{})
+	bytes, err := d.marshal()
 	if err != nil {
 		return nil, err
 	}
 
-	em, err := cbor.CanonicalEncOptions().EncModeWithTags(tags)
-	if err != nil {
-		return nil, err
+	return d.original.bytes(bytes), nil
+}
+
+func (d *AuxiliaryData) marshal() ([]byte, error) {
+	type auxiliaryData AuxiliaryData
+
+	metadata := d.Metadata
+	if metadata == nil {
+		metadata = Metadata{}
 	}
+	hasPlutusScripts := len(d.PlutusV1Scripts) > 0 || len(d.PlutusV2Scripts) > 0 || len(d.PlutusV3Scripts) > 0
+
+	switch d.Format {
+	case AlonzoAuxiliaryData:
+		// Register tag 259 for maps
+		tags, err := d.tagSet(auxiliaryData{})
+		if err != nil {
+			return nil, err
+		}
 
-	return em.Marshal(auxiliaryData(*d))
+		em, err := cbor.CanonicalEncOptions().EncModeWithTags(tags)
+		if err != nil {
+			return nil, err
+		}
+
+		return em.Marshal(auxiliaryData(*d))
+	case ShelleyAuxiliaryData:
+		if len(d.NativeScripts) > 0 || hasPlutusScripts {
+			return nil, errors.New("shelley auxiliary data can't contain scripts")
+		}
+		return cborEnc.Marshal(metadata)
+	case ShelleyMAAuxiliaryData:
+		if hasPlutusScripts {
+			return nil, errors.New("allegra/mary auxiliary data can't contain plutus scripts")
+		}
+		nativeScripts := d.NativeScripts
+		if nativeScripts == nil {
+			nativeScripts = []NativeScript{}
+		}
+		return cborEnc.Marshal(shelleyMAAuxiliaryData{Metadata: metadata, NativeScripts: nativeScripts})
+	default:
+		return nil, fmt.Errorf("invalid auxiliary data format %d", d.Format)
+	}
 }
 
 // UnmarshalCBOR implements cbor.Unmarshaler
 func (d *AuxiliaryData) UnmarshalCBOR(data []byte) error {
 	type auxiliaryData AuxiliaryData
 
-	// Register tag 259 for maps
-	tags, err := d.tagSet(auxiliaryData{})
+	major, _, _, _, err := decodeCBORHead(data)
 	if err != nil {
 		return err
 	}
 
-	dm, err := cbor.DecOptions{
-		MapKeyByteString: cbor.MapKeyByteStringWrap,
-	}.DecModeWithTags(tags)
-	if err != nil {
-		return err
+	var dd auxiliaryData
+	switch major {
+	case 6:
+		// Register tag 259 for maps
+		tags, err := d.tagSet(auxiliaryData{})
+		if err != nil {
+			return err
+		}
+
+		dm, err := cbor.DecOptions{
+			MapKeyByteString: cbor.MapKeyByteStringWrap,
+		}.DecModeWithTags(tags)
+		if err != nil {
+			return err
+		}
+
+		if err := dm.Unmarshal(data, &dd); err != nil {
+			return err
+		}
+		dd.Format = AlonzoAuxiliaryData
+	case 5:
+		if err := cborDec.Unmarshal(data, &dd.Metadata); err != nil {
+			return err
+		}
+		dd.Format = ShelleyAuxiliaryData
+	case 4:
+		var ma shelleyMAAuxiliaryData
+		if err := cborDec.Unmarshal(data, &ma); err != nil {
+			return err
+		}
+		dd.Metadata = ma.Metadata
+		dd.NativeScripts = ma.NativeScripts
+		dd.Format = ShelleyMAAuxiliaryData
+	default:
+		return fmt.Errorf("cbor: cannot unmarshal CBOR major type %d into AuxiliaryData", major)
 	}
 
-	var dd auxiliaryData
-	if err := dm.Unmarshal(data, &dd); err != nil {
+	*d = AuxiliaryData(dd)
+	if len(d.Metadata) == 0 {
+		d.Metadata = nil
+	}
+	if len(d.NativeScripts) == 0 {
+		d.NativeScripts = nil
+	}
+
+	encoded, err := d.marshal()
+	if err != nil {
 		return err
 	}
-	d.Metadata = dd.Metadata
+	d.original = newOriginalCBOR(data, encoded)
 
 	return nil
 }
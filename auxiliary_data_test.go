@@ -0,0 +1,106 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestAuxiliaryDataEncoding(t *testing.T) {
+	keyHash, err := hex.DecodeString("1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name    string
+		cborHex string
+		auxData AuxiliaryData
+	}{
+		{
+			name:    "Shelley",
+			cborHex: "a10163666f6f",
+			auxData: AuxiliaryData{
+				Metadata: Metadata{1: "foo"},
+				Format:   ShelleyAuxiliaryData,
+			},
+		},
+		{
+			name:    "ShelleyMA",
+			cborHex: "82a10163666f6f818200581c1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361",
+			auxData: AuxiliaryData{
+				Metadata:      Metadata{1: "foo"},
+				NativeScripts: []NativeScript{{Type: ScriptPubKey, KeyHash: keyHash}},
+				Format:        ShelleyMAAuxiliaryData,
+			},
+		},
+		{
+			name:    "ShelleyMAWithoutScripts",
+			cborHex: "82a10163666f6f80",
+			auxData: AuxiliaryData{
+				Metadata: Metadata{1: "foo"},
+				Format:   ShelleyMAAuxiliaryData,
+			},
+		},
+		{
+			name:    "Alonzo",
+			cborHex: "d90103a300a10163666f6f01818200581c1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d5336102814401020304",
+			auxData: AuxiliaryData{
+				Metadata:        Metadata{1: "foo"},
+				NativeScripts:   []NativeScript{{Type: ScriptPubKey, KeyHash: keyHash}},
+				PlutusV1Scripts: []PlutusV1Script{{1, 2, 3, 4}},
+				Format:          AlonzoAuxiliaryData,
+			},
+		},
+		{
+			name:    "AlonzoPlutusV3",
+			cborHex: "d90103a104814401020304",
+			auxData: AuxiliaryData{
+				PlutusV3Scripts: []PlutusV3Script{{1, 2, 3, 4}},
+				Format:          AlonzoAuxiliaryData,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.cborHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var auxData AuxiliaryData
+			if err := cborDec.Unmarshal(data, &auxData); err != nil {
+				t.Fatal(err)
+			}
+			auxData.original = originalCBOR{}
+			if !reflect.DeepEqual(auxData, tc.auxData) {
+				t.Errorf("invalid auxiliary data\ngot: %+v\nwant: %+v", auxData, tc.auxData)
+			}
+
+			encoded, err := cborEnc.Marshal(&tc.auxData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := hex.EncodeToString(encoded), tc.cborHex; got != want {
+				t.Errorf("invalid encoding\ngot: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestAuxiliaryDataInvalidFormat(t *testing.T) {
+	auxData := AuxiliaryData{
+		Metadata:        Metadata{1: "foo"},
+		PlutusV1Scripts: []PlutusV1Script{{1, 2, 3, 4}},
+		Format:          ShelleyMAAuxiliaryData,
+	}
+	if _, err := cborEnc.Marshal(&auxData); err == nil {
+		t.Errorf("expected error encoding plutus scripts in allegra/mary auxiliary data")
+	}
+
+	auxData.Format = ShelleyAuxiliaryData
+	if _, err := cborEnc.Marshal(&auxData); err == nil {
+		t.Errorf("expected error encoding scripts in shelley auxiliary data")
+	}
+}
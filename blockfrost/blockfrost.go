@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -18,6 +19,7 @@ import (
 type BlockfrostNode struct {
 	client    blockfrost.APIClient
 	projectID string
+	server    string
 	network   cardano.Network
 }
 
@@ -35,6 +37,7 @@ func NewNode(network cardano.Network, projectID string) cardano.Node {
 	return &BlockfrostNode{
 		network:   network,
 		projectID: projectID,
+		server:    server,
 		client: blockfrost.NewAPIClient(blockfrost.APIClientOptions{
 			ProjectID: projectID,
 			Server:    server,
@@ -159,22 +162,106 @@ func (b *BlockfrostNode) SubmitTx(tx *cardano.Tx) (*cardano.Hash32, error) {
 	return &txHash, nil
 }
 
-func (b *BlockfrostNode) ProtocolParams() (*cardano.ProtocolParams, error) {
-	eparams, err := b.client.LatestEpochParameters(context.Background())
+// epochParameters are the protocol parameters returned by the blockfrost API. The
+// client doesn't support the parameters introduced after Mary, so they're fetched
+// directly from the epochs endpoint.
+type epochParameters struct {
+	MinFeeA                    uint64      `json:"min_fee_a"`
+	MinFeeB                    uint64      `json:"min_fee_b"`
+	MaxBlockSize               uint        `json:"max_block_size"`
+	MaxTxSize                  uint        `json:"max_tx_size"`
+	MaxBlockHeaderSize         uint        `json:"max_block_header_size"`
+	KeyDeposit                 string      `json:"key_deposit"`
+	PoolDeposit                string      `json:"pool_deposit"`
+	Epoch                      uint        `json:"epoch"`
+	NOpt                       uint        `json:"n_opt"`
+	ProtocolMajorVer           uint        `json:"protocol_major_ver"`
+	ProtocolMinorVer           uint        `json:"protocol_minor_ver"`
+	MinUtxo                    string      `json:"min_utxo"`
+	PriceMem                   json.Number `json:"price_mem"`
+	PriceStep                  json.Number `json:"price_step"`
+	MaxTxExMem                 string      `json:"max_tx_ex_mem"`
+	MaxTxExSteps               string      `json:"max_tx_ex_steps"`
+	MaxBlockExMem              string      `json:"max_block_ex_mem"`
+	MaxBlockExSteps            string      `json:"max_block_ex_steps"`
+	MaxValSize                 string      `json:"max_val_size"`
+	CollateralPercent          uint        `json:"collateral_percent"`
+	MaxCollateralInputs        uint        `json:"max_collateral_inputs"`
+	MinFeeRefScriptCostPerByte json.Number `json:"min_fee_ref_script_cost_per_byte"`
+}
+
+func (b *BlockfrostNode) latestEpochParameters() (*epochParameters, error) {
+	req, err := http.NewRequest("GET", b.server+"/epochs/latest/parameters", nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add("project_id", b.projectID)
 
-	minUTXO, err := strconv.ParseUint(eparams.MinUtxo, 10, 64)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	poolDeposit, err := strconv.ParseUint(eparams.PoolDeposit, 10, 64)
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	keyDeposit, err := strconv.ParseUint(eparams.KeyDeposit, 10, 64)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(string(respBody))
+	}
+
+	eparams := &epochParameters{}
+	if err := json.Unmarshal(respBody, eparams); err != nil {
+		return nil, err
+	}
+	return eparams, nil
+}
+
+func (b *BlockfrostNode) ProtocolParams() (*cardano.ProtocolParams, error) {
+	eparams, err := b.latestEpochParameters()
+	if err != nil {
+		return nil, err
+	}
+	return newProtocolParams(eparams)
+}
+
+func newProtocolParams(eparams *epochParameters) (*cardano.ProtocolParams, error) {
+	var err error
+	var minUTXO, poolDeposit, keyDeposit, maxValSize uint64
+	var maxTxExMem, maxTxExSteps, maxBlockExMem, maxBlockExSteps uint64
+	for _, param := range []struct {
+		value string
+		dst   *uint64
+	}{
+		{eparams.MinUtxo, &minUTXO},
+		{eparams.PoolDeposit, &poolDeposit},
+		{eparams.KeyDeposit, &keyDeposit},
+		{eparams.MaxValSize, &maxValSize},
+		{eparams.MaxTxExMem, &maxTxExMem},
+		{eparams.MaxTxExSteps, &maxTxExSteps},
+		{eparams.MaxBlockExMem, &maxBlockExMem},
+		{eparams.MaxBlockExSteps, &maxBlockExSteps},
+	} {
+		// Parameters of later eras are null
+		if param.value == "" {
+			continue
+		}
+		if *param.dst, err = strconv.ParseUint(param.value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	priceMem, err := cardano.NewRationalFromString(eparams.PriceMem.String())
+	if err != nil {
+		return nil, err
+	}
+	priceStep, err := cardano.NewRationalFromString(eparams.PriceStep.String())
+	if err != nil {
+		return nil, err
+	}
+	refScriptCost, err := cardano.NewRationalFromString(eparams.MinFeeRefScriptCostPerByte.String())
 	if err != nil {
 		return nil, err
 	}
@@ -182,14 +269,40 @@ func (b *BlockfrostNode) ProtocolParams() (*cardano.ProtocolParams, error) {
 	pparams := &cardano.ProtocolParams{
 		MinFeeA:            cardano.Coin(eparams.MinFeeA),
 		MinFeeB:            cardano.Coin(eparams.MinFeeB),
-		MaxBlockBodySize:   uint(eparams.MaxBlockSize),
-		MaxTxSize:          uint(eparams.MaxTxSize),
-		MaxBlockHeaderSize: uint(eparams.MaxBlockHeaderSize),
+		MaxBlockBodySize:   eparams.MaxBlockSize,
+		MaxTxSize:          eparams.MaxTxSize,
+		MaxBlockHeaderSize: eparams.MaxBlockHeaderSize,
 		KeyDeposit:         cardano.Coin(keyDeposit),
 		PoolDeposit:        cardano.Coin(poolDeposit),
-		MaxEpoch:           uint(eparams.Epoch),
-		NOpt:               uint(eparams.NOpt),
-		CoinsPerUTXOWord:   cardano.Coin(minUTXO),
+		MaxEpoch:           eparams.Epoch,
+		NOpt:               eparams.NOpt,
+		ProtocolVersion: cardano.ProtocolVersion{
+			Major: eparams.ProtocolMajorVer,
+			Minor: eparams.ProtocolMinorVer,
+		},
+		ExecutionCosts: cardano.ExUnitPrices{
+			Mem:   priceMem,
+			Steps: priceStep,
+		},
+		MaxTxExUnits: cardano.ExUnits{
+			Mem:   maxTxExMem,
+			Steps: maxTxExSteps,
+		},
+		MaxBlockTxExUnits: cardano.ExUnits{
+			Mem:   maxBlockExMem,
+			Steps: maxBlockExSteps,
+		},
+		MaxValueSize:               uint(maxValSize),
+		CollateralPercentage:       eparams.CollateralPercent,
+		MaxCollateralInputs:        eparams.MaxCollateralInputs,
+		MinFeeRefScriptCostPerByte: refScriptCost,
+	}
+
+	// The minimum UTxO parameter is the cost per byte since Babbage
+	if pparams.IsBabbage() {
+		pparams.CoinsPerUTxOByte = cardano.Coin(minUTXO)
+	} else {
+		pparams.CoinsPerUTXOWord = cardano.Coin(minUTXO)
 	}
 
 	return pparams, nil
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/echovl/cardano-go"
+	"github.com/echovl/cardano-go/textenvelope"
 )
 
 // CardanoCli implements Node using cardano-cli and a local node.
@@ -28,12 +29,6 @@ type tip struct {
 	Era   string
 }
 
-type cliTx struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
-	CborHex     string `json:"cborHex"`
-}
-
 // NewNode returns a new instance of CardanoCli.
 func NewNode(network cardano.Network) cardano.Node {
 	return &CardanoCli{network: network}
@@ -154,9 +149,9 @@ func (c *CardanoCli) Tip() (*cardano.NodeTip, error) {
 }
 
 func (c *CardanoCli) SubmitTx(tx *cardano.Tx) (*cardano.Hash32, error) {
-	txOut := cliTx{
-		Type:    "Witnessed Tx AlonzoEra",
-		CborHex: tx.Hex(),
+	txOut, err := textenvelope.NewTx(tx, textenvelope.AlonzoEra)
+	if err != nil {
+		return nil, err
 	}
 
 	txFile, err := ioutil.TempFile(os.TempDir(), "tx_")
@@ -165,7 +160,7 @@ func (c *CardanoCli) SubmitTx(tx *cardano.Tx) (*cardano.Hash32, error) {
 	}
 	defer os.Remove(txFile.Name())
 
-	if err := json.NewEncoder(txFile).Encode(txOut); err != nil {
+	if err := txOut.Write(txFile); err != nil {
 		return nil, err
 	}
 
@@ -186,6 +181,18 @@ type protocolParameters struct {
 	MinFeeA          cardano.Coin `json:"txFeePerByte"`
 	MinFeeB          cardano.Coin `json:"txFeeFixed"`
 	CoinsPerUTXOWord cardano.Coin `json:"utxoCostPerWord"`
+	CoinsPerUTxOByte cardano.Coin `json:"utxoCostPerByte"`
+	MaxTxSize        uint         `json:"maxTxSize"`
+	MaxValueSize     uint         `json:"maxValueSize"`
+	ProtocolVersion  struct {
+		Major uint `json:"major"`
+		Minor uint `json:"minor"`
+	} `json:"protocolVersion"`
+	ExecutionUnitPrices struct {
+		PriceMemory json.Number `json:"priceMemory"`
+		PriceSteps  json.Number `json:"priceSteps"`
+	} `json:"executionUnitPrices"`
+	MinFeeRefScriptCostPerByte json.Number `json:"minFeeRefScriptCostPerByte"`
 }
 
 func (c *CardanoCli) ProtocolParams() (*cardano.ProtocolParams, error) {
@@ -203,6 +210,24 @@ func (c *CardanoCli) ProtocolParams() (*cardano.ProtocolParams, error) {
 		MinFeeA:          cparams.MinFeeA,
 		MinFeeB:          cparams.MinFeeB,
 		CoinsPerUTXOWord: cparams.CoinsPerUTXOWord,
+		CoinsPerUTxOByte: cparams.CoinsPerUTxOByte,
+		MaxTxSize:        cparams.MaxTxSize,
+		MaxValueSize:     cparams.MaxValueSize,
+		ProtocolVersion: cardano.ProtocolVersion{
+			Major: cparams.ProtocolVersion.Major,
+			Minor: cparams.ProtocolVersion.Minor,
+		},
+	}
+
+	prices := cparams.ExecutionUnitPrices
+	if pparams.ExecutionCosts.Mem, err = cardano.NewRationalFromString(prices.PriceMemory.String()); err != nil {
+		return nil, err
+	}
+	if pparams.ExecutionCosts.Steps, err = cardano.NewRationalFromString(prices.PriceSteps.String()); err != nil {
+		return nil, err
+	}
+	if pparams.MinFeeRefScriptCostPerByte, err = cardano.NewRationalFromString(cparams.MinFeeRefScriptCostPerByte.String()); err != nil {
+		return nil, err
 	}
 
 	return pparams, nil
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/cbor"
 )
 
 type CertificateType uint
@@ -16,6 +17,18 @@ const (
 	PoolRetirement
 	GenesisKeyDelegation
 	MoveInstantaneousRewards
+	Registration
+	Unregistration
+	VoteDelegation
+	StakeVoteDelegation
+	StakeRegistrationDelegation
+	VoteRegistrationDelegation
+	StakeVoteRegistrationDelegation
+	AuthCommitteeHot
+	ResignCommitteeCold
+	DRepRegistration
+	DRepDeregistration
+	DRepUpdate
 )
 
 type stakeRegistration struct {
@@ -65,6 +78,115 @@ type genesisKeyDelegation struct {
 	VrfKeyHash          Hash32
 }
 
+type moveInstantaneousRewards struct {
+	_    struct{} `cbor:",toarray"`
+	Type CertificateType
+	MIR  cbor.RawMessage
+}
+
+type MIRPot uint64
+
+const (
+	ReservesMIR MIRPot = iota
+	TreasuryMIR
+)
+
+// MIRReward is the reward moved to a stake credential by a MIR certificate.
+type MIRReward struct {
+	StakeCredential StakeCredential
+	Delta           int64
+}
+
+type registration struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	Deposit         Coin
+}
+
+type unregistration struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	Deposit         Coin
+}
+
+type voteDelegation struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	DRep            DRep
+}
+
+type stakeVoteDelegation struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	PoolKeyHash     PoolKeyHash
+	DRep            DRep
+}
+
+type stakeRegistrationDelegation struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	PoolKeyHash     PoolKeyHash
+	Deposit         Coin
+}
+
+type voteRegistrationDelegation struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	DRep            DRep
+	Deposit         Coin
+}
+
+type stakeVoteRegistrationDelegation struct {
+	_               struct{} `cbor:",toarray"`
+	Type            CertificateType
+	StakeCredential StakeCredential
+	PoolKeyHash     PoolKeyHash
+	DRep            DRep
+	Deposit         Coin
+}
+
+type authCommitteeHot struct {
+	_              struct{} `cbor:",toarray"`
+	Type           CertificateType
+	ColdCredential StakeCredential
+	HotCredential  StakeCredential
+}
+
+type resignCommitteeCold struct {
+	_              struct{} `cbor:",toarray"`
+	Type           CertificateType
+	ColdCredential StakeCredential
+	Anchor         *Anchor // or null
+}
+
+type drepRegistration struct {
+	_              struct{} `cbor:",toarray"`
+	Type           CertificateType
+	DRepCredential StakeCredential
+	Deposit        Coin
+	Anchor         *Anchor // or null
+}
+
+type drepDeregistration struct {
+	_              struct{} `cbor:",toarray"`
+	Type           CertificateType
+	DRepCredential StakeCredential
+	Deposit        Coin
+}
+
+type drepUpdate struct {
+	_              struct{} `cbor:",toarray"`
+	Type           CertificateType
+	DRepCredential StakeCredential
+	Anchor         *Anchor // or null
+}
+
 // Certificate is a Cardano certificate.
 type Certificate struct {
 	Type CertificateType
@@ -87,6 +209,19 @@ type Certificate struct {
 	// Genesis fields
 	GenesisHash         Hash28
 	GenesisDelegateHash Hash28
+
+	// Move instantaneous rewards fields
+	MIRPot     MIRPot
+	MIRRewards []MIRReward // rewards moved to stake credentials
+	MIRToPot   *Coin       // or amount moved to the other pot
+
+	// Conway fields
+	Deposit        Coin
+	DRep           DRep
+	DRepCredential StakeCredential
+	ColdCredential StakeCredential
+	HotCredential  StakeCredential
+	Anchor         *Anchor // or null
 }
 
 // MarshalCBOR implements cbor.Marshaler.
@@ -134,6 +269,93 @@ func (c *Certificate) MarshalCBOR() ([]byte, error) {
 			GenesisDelegateHash: c.GenesisDelegateHash,
 			VrfKeyHash:          c.VrfKeyHash,
 		}
+	case MoveInstantaneousRewards:
+		mir, err := c.marshalMIR()
+		if err != nil {
+			return nil, err
+		}
+		cert = moveInstantaneousRewards{
+			Type: c.Type,
+			MIR:  mir,
+		}
+	case Registration:
+		cert = registration{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			Deposit:         c.Deposit,
+		}
+	case Unregistration:
+		cert = unregistration{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			Deposit:         c.Deposit,
+		}
+	case VoteDelegation:
+		cert = voteDelegation{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			DRep:            c.DRep,
+		}
+	case StakeVoteDelegation:
+		cert = stakeVoteDelegation{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			PoolKeyHash:     c.PoolKeyHash,
+			DRep:            c.DRep,
+		}
+	case StakeRegistrationDelegation:
+		cert = stakeRegistrationDelegation{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			PoolKeyHash:     c.PoolKeyHash,
+			Deposit:         c.Deposit,
+		}
+	case VoteRegistrationDelegation:
+		cert = voteRegistrationDelegation{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			DRep:            c.DRep,
+			Deposit:         c.Deposit,
+		}
+	case StakeVoteRegistrationDelegation:
+		cert = stakeVoteRegistrationDelegation{
+			Type:            c.Type,
+			StakeCredential: c.StakeCredential,
+			PoolKeyHash:     c.PoolKeyHash,
+			DRep:            c.DRep,
+			Deposit:         c.Deposit,
+		}
+	case AuthCommitteeHot:
+		cert = authCommitteeHot{
+			Type:           c.Type,
+			ColdCredential: c.ColdCredential,
+			HotCredential:  c.HotCredential,
+		}
+	case ResignCommitteeCold:
+		cert = resignCommitteeCold{
+			Type:           c.Type,
+			ColdCredential: c.ColdCredential,
+			Anchor:         c.Anchor,
+		}
+	case DRepRegistration:
+		cert = drepRegistration{
+			Type:           c.Type,
+			DRepCredential: c.DRepCredential,
+			Deposit:        c.Deposit,
+			Anchor:         c.Anchor,
+		}
+	case DRepDeregistration:
+		cert = drepDeregistration{
+			Type:           c.Type,
+			DRepCredential: c.DRepCredential,
+			Deposit:        c.Deposit,
+		}
+	case DRepUpdate:
+		cert = drepUpdate{
+			Type:           c.Type,
+			DRepCredential: c.DRepCredential,
+			Anchor:         c.Anchor,
+		}
 	}
 
 	return cborEnc.Marshal(cert)
@@ -179,6 +401,145 @@ func NewStakeDelegationCertificate(stakeKey crypto.PubKey, poolKeyHash Hash28) (
 	}, nil
 }
 
+// NewMIRCertificate creates a Move Instantaneous Rewards Certificate that moves
+// rewards from the pot to the stake credentials.
+func NewMIRCertificate(pot MIRPot, rewards ...MIRReward) Certificate {
+	return Certificate{
+		Type:       MoveInstantaneousRewards,
+		MIRPot:     pot,
+		MIRRewards: rewards,
+	}
+}
+
+// NewMIRToPotCertificate creates a Move Instantaneous Rewards Certificate that
+// moves an amount from the pot to the other pot.
+func NewMIRToPotCertificate(pot MIRPot, amount Coin) Certificate {
+	return Certificate{
+		Type:     MoveInstantaneousRewards,
+		MIRPot:   pot,
+		MIRToPot: &amount,
+	}
+}
+
+// NewRegistrationCertificate creates a Conway Stake Registration Certificate
+// with an explicit deposit.
+func NewRegistrationCertificate(stakeKey crypto.PubKey, deposit Coin) (Certificate, error) {
+	cred, err := NewKeyCredential(stakeKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:            Registration,
+		StakeCredential: cred,
+		Deposit:         deposit,
+	}, nil
+}
+
+// NewUnregistrationCertificate creates a Conway Stake Deregistration Certificate
+// with an explicit deposit refund.
+func NewUnregistrationCertificate(stakeKey crypto.PubKey, deposit Coin) (Certificate, error) {
+	cred, err := NewKeyCredential(stakeKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:            Unregistration,
+		StakeCredential: cred,
+		Deposit:         deposit,
+	}, nil
+}
+
+// NewVoteDelegationCertificate creates a Vote Delegation Certificate.
+func NewVoteDelegationCertificate(stakeKey crypto.PubKey, drep DRep) (Certificate, error) {
+	cred, err := NewKeyCredential(stakeKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:            VoteDelegation,
+		StakeCredential: cred,
+		DRep:            drep,
+	}, nil
+}
+
+// NewDRepRegistrationCertificate creates a DRep Registration Certificate.
+func NewDRepRegistrationCertificate(drepKey crypto.PubKey, deposit Coin, anchor *Anchor) (Certificate, error) {
+	cred, err := NewKeyCredential(drepKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:           DRepRegistration,
+		DRepCredential: cred,
+		Deposit:        deposit,
+		Anchor:         anchor,
+	}, nil
+}
+
+// NewDRepDeregistrationCertificate creates a DRep Deregistration Certificate.
+func NewDRepDeregistrationCertificate(drepKey crypto.PubKey, deposit Coin) (Certificate, error) {
+	cred, err := NewKeyCredential(drepKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:           DRepDeregistration,
+		DRepCredential: cred,
+		Deposit:        deposit,
+	}, nil
+}
+
+// NewDRepUpdateCertificate creates a DRep Update Certificate.
+func NewDRepUpdateCertificate(drepKey crypto.PubKey, anchor *Anchor) (Certificate, error) {
+	cred, err := NewKeyCredential(drepKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:           DRepUpdate,
+		DRepCredential: cred,
+		Anchor:         anchor,
+	}, nil
+}
+
+// NewAuthCommitteeHotCertificate creates a Certificate authorizing a committee hot key.
+func NewAuthCommitteeHotCertificate(coldKey, hotKey crypto.PubKey) (Certificate, error) {
+	coldCred, err := NewKeyCredential(coldKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+	hotCred, err := NewKeyCredential(hotKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:           AuthCommitteeHot,
+		ColdCredential: coldCred,
+		HotCredential:  hotCred,
+	}, nil
+}
+
+// NewResignCommitteeColdCertificate creates a Certificate resigning a committee cold key.
+func NewResignCommitteeColdCertificate(coldKey crypto.PubKey, anchor *Anchor) (Certificate, error) {
+	cred, err := NewKeyCredential(coldKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	return Certificate{
+		Type:           ResignCommitteeCold,
+		ColdCredential: cred,
+		Anchor:         anchor,
+	}, nil
+}
+
 // UnmarshalCBOR implements cbor.Unmarshaler.
 func (c *Certificate) UnmarshalCBOR(data []byte) error {
 	certType, err := getTypeFromCBORArray(data)
@@ -240,8 +601,183 @@ func (c *Certificate) UnmarshalCBOR(data []byte) error {
 		c.GenesisHash = cert.GenesisHash
 		c.GenesisDelegateHash = cert.GenesisDelegateHash
 		c.VrfKeyHash = cert.VrfKeyHash
+	case MoveInstantaneousRewards:
+		cert := &moveInstantaneousRewards{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = MoveInstantaneousRewards
+		if err := c.unmarshalMIR(cert.MIR); err != nil {
+			return err
+		}
+	case Registration:
+		cert := &registration{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = Registration
+		c.StakeCredential = cert.StakeCredential
+		c.Deposit = cert.Deposit
+	case Unregistration:
+		cert := &unregistration{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = Unregistration
+		c.StakeCredential = cert.StakeCredential
+		c.Deposit = cert.Deposit
+	case VoteDelegation:
+		cert := &voteDelegation{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = VoteDelegation
+		c.StakeCredential = cert.StakeCredential
+		c.DRep = cert.DRep
+	case StakeVoteDelegation:
+		cert := &stakeVoteDelegation{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = StakeVoteDelegation
+		c.StakeCredential = cert.StakeCredential
+		c.PoolKeyHash = cert.PoolKeyHash
+		c.DRep = cert.DRep
+	case StakeRegistrationDelegation:
+		cert := &stakeRegistrationDelegation{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = StakeRegistrationDelegation
+		c.StakeCredential = cert.StakeCredential
+		c.PoolKeyHash = cert.PoolKeyHash
+		c.Deposit = cert.Deposit
+	case VoteRegistrationDelegation:
+		cert := &voteRegistrationDelegation{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = VoteRegistrationDelegation
+		c.StakeCredential = cert.StakeCredential
+		c.DRep = cert.DRep
+		c.Deposit = cert.Deposit
+	case StakeVoteRegistrationDelegation:
+		cert := &stakeVoteRegistrationDelegation{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = StakeVoteRegistrationDelegation
+		c.StakeCredential = cert.StakeCredential
+		c.PoolKeyHash = cert.PoolKeyHash
+		c.DRep = cert.DRep
+		c.Deposit = cert.Deposit
+	case AuthCommitteeHot:
+		cert := &authCommitteeHot{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = AuthCommitteeHot
+		c.ColdCredential = cert.ColdCredential
+		c.HotCredential = cert.HotCredential
+	case ResignCommitteeCold:
+		cert := &resignCommitteeCold{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = ResignCommitteeCold
+		c.ColdCredential = cert.ColdCredential
+		c.Anchor = cert.Anchor
+	case DRepRegistration:
+		cert := &drepRegistration{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = DRepRegistration
+		c.DRepCredential = cert.DRepCredential
+		c.Deposit = cert.Deposit
+		c.Anchor = cert.Anchor
+	case DRepDeregistration:
+		cert := &drepDeregistration{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = DRepDeregistration
+		c.DRepCredential = cert.DRepCredential
+		c.Deposit = cert.Deposit
+	case DRepUpdate:
+		cert := &drepUpdate{}
+		if err := cborDec.Unmarshal(data, cert); err != nil {
+			return err
+		}
+		c.Type = DRepUpdate
+		c.DRepCredential = cert.DRepCredential
+		c.Anchor = cert.Anchor
+	}
+
+	return nil
+}
+
+// marshalMIR encodes the MIR as [pot, { * stake_credential => delta_coin } / coin].
+func (c *Certificate) marshalMIR() ([]byte, error) {
+	mir := append(encodeCBORHead(4, 2), encodeCBORHead(0, uint64(c.MIRPot))...)
+	if c.MIRToPot != nil {
+		return append(mir, encodeCBORHead(0, uint64(*c.MIRToPot))...), nil
+	}
+
+	mir = append(mir, encodeCBORHead(5, uint64(len(c.MIRRewards)))...)
+	for _, reward := range c.MIRRewards {
+		cred, err := reward.StakeCredential.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		delta, err := cborEnc.Marshal(reward.Delta)
+		if err != nil {
+			return nil, err
+		}
+		mir = append(mir, cred...)
+		mir = append(mir, delta...)
 	}
+	return mir, nil
+}
 
+func (c *Certificate) unmarshalMIR(data []byte) error {
+	var mir []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &mir); err != nil {
+		return err
+	}
+	if len(mir) != 2 {
+		return fmt.Errorf("cbor: invalid MIR, expected 2 elements got %d", len(mir))
+	}
+	if err := cborDec.Unmarshal(mir[0], &c.MIRPot); err != nil {
+		return err
+	}
+
+	major, _, _, _, err := decodeCBORHead(mir[1])
+	if err != nil {
+		return err
+	}
+	if major != 5 {
+		var amount Coin
+		if err := cborDec.Unmarshal(mir[1], &amount); err != nil {
+			return err
+		}
+		c.MIRToPot = &amount
+		return nil
+	}
+
+	pairs, err := getMapPairsFromCBOR(mir[1])
+	if err != nil {
+		return err
+	}
+	c.MIRRewards = make([]MIRReward, len(pairs))
+	for i, pair := range pairs {
+		if err := cborDec.Unmarshal(pair[0], &c.MIRRewards[i].StakeCredential); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(pair[1], &c.MIRRewards[i].Delta); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
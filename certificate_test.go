@@ -0,0 +1,144 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestConwayCertificateEncoding(t *testing.T) {
+	keyHash := make(AddrKeyHash, 28)
+	cred := StakeCredential{Type: KeyCredential, KeyHash: keyHash}
+	dataHash := make(Hash32, 32)
+	anchor := &Anchor{URL: "https://a.io", DataHash: dataHash}
+
+	const credHex = "8200581c00000000000000000000000000000000000000000000000000000000"
+	const anchorHex = "826c68747470733a2f2f612e696f58200000000000000000000000000000000000000000000000000000000000000000"
+
+	testcases := []struct {
+		name string
+		cert Certificate
+		want string
+	}{
+		{
+			name: "registration",
+			cert: Certificate{Type: Registration, StakeCredential: cred, Deposit: 2e6},
+			want: "8307" + credHex + "1a001e8480",
+		},
+		{
+			name: "unregistration",
+			cert: Certificate{Type: Unregistration, StakeCredential: cred, Deposit: 2e6},
+			want: "8308" + credHex + "1a001e8480",
+		},
+		{
+			name: "vote delegation",
+			cert: Certificate{Type: VoteDelegation, StakeCredential: cred, DRep: DRep{Type: DRepAlwaysAbstain}},
+			want: "8309" + credHex + "8102",
+		},
+		{
+			name: "stake vote registration delegation",
+			cert: Certificate{
+				Type:            StakeVoteRegistrationDelegation,
+				StakeCredential: cred,
+				PoolKeyHash:     keyHash,
+				DRep:            DRep{Type: DRepKeyHash, KeyHash: keyHash},
+				Deposit:         2e6,
+			},
+			want: "850d" + credHex + "581c00000000000000000000000000000000000000000000000000000000" + credHex + "1a001e8480",
+		},
+		{
+			name: "auth committee hot",
+			cert: Certificate{Type: AuthCommitteeHot, ColdCredential: cred, HotCredential: cred},
+			want: "830e" + credHex + credHex,
+		},
+		{
+			name: "resign committee cold",
+			cert: Certificate{Type: ResignCommitteeCold, ColdCredential: cred},
+			want: "830f" + credHex + "f6",
+		},
+		{
+			name: "drep registration",
+			cert: Certificate{Type: DRepRegistration, DRepCredential: cred, Deposit: 5e8, Anchor: anchor},
+			want: "8410" + credHex + "1a1dcd6500" + anchorHex,
+		},
+		{
+			name: "drep deregistration",
+			cert: Certificate{Type: DRepDeregistration, DRepCredential: cred, Deposit: 5e8},
+			want: "8311" + credHex + "1a1dcd6500",
+		},
+		{
+			name: "drep update",
+			cert: Certificate{Type: DRepUpdate, DRepCredential: cred, Anchor: anchor},
+			want: "8312" + credHex + anchorHex,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.cert.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tc.want {
+				t.Errorf("invalid certificate encoding\ngot: %x\nwant: %s", got, tc.want)
+			}
+
+			decoded := Certificate{}
+			if err := decoded.UnmarshalCBOR(got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, tc.cert) {
+				t.Errorf("invalid certificate decoding\ngot: %+v\nwant: %+v", decoded, tc.cert)
+			}
+		})
+	}
+}
+
+func TestMIRCertificateEncoding(t *testing.T) {
+	keyHash := make(AddrKeyHash, 28)
+	scriptHash := make(Hash28, 28)
+	scriptHash[0] = 1
+
+	testcases := []struct {
+		name string
+		cert Certificate
+		want string
+	}{
+		{
+			name: "stake credentials",
+			cert: NewMIRCertificate(
+				TreasuryMIR,
+				MIRReward{StakeCredential: StakeCredential{Type: KeyCredential, KeyHash: keyHash}, Delta: 1000},
+				MIRReward{StakeCredential: StakeCredential{Type: ScriptCredential, ScriptHash: scriptHash}, Delta: -10},
+			),
+			want: "82068201a2" +
+				"8200581c00000000000000000000000000000000000000000000000000000000" + "1903e8" +
+				"8201581c01000000000000000000000000000000000000000000000000000000" + "29",
+		},
+		{
+			name: "other pot",
+			cert: NewMIRToPotCertificate(ReservesMIR, 1e6),
+			want: "820682001a000f4240",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.cert.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tc.want {
+				t.Errorf("invalid certificate encoding\ngot: %x\nwant: %s", got, tc.want)
+			}
+
+			decoded := Certificate{}
+			if err := decoded.UnmarshalCBOR(got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, tc.cert) {
+				t.Errorf("invalid certificate decoding\ngot: %+v\nwant: %+v", decoded, tc.cert)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package cardano
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/echovl/cardano-go/internal/cbor"
+)
+
+// CoinSelection selects the inputs required to cover a target value.
+// More info could be found in
+// <https://github.com/cardano-foundation/CIPs/tree/master/CIP-0002>
+type CoinSelection interface {
+	// Select returns a subset of the available inputs whose total value is
+	// greater than or equal to the target.
+	Select(available []*TxInput, target *Value) ([]*TxInput, error)
+}
+
+// LargestFirst selects the inputs with the largest amounts first, one asset at a time.
+type LargestFirst struct{}
+
+// Select implements CoinSelection.
+func (LargestFirst) Select(available []*TxInput, target *Value) ([]*TxInput, error) {
+	selection := newInputSelection(available)
+	for _, asset := range selectionAssets(target) {
+		remaining := selection.remaining(asset)
+		sort.SliceStable(remaining, func(i, j int) bool {
+			return asset.quantity(remaining[i].Amount) > asset.quantity(remaining[j].Amount)
+		})
+		for selection.quantity(asset) < asset.quantity(target) {
+			if len(remaining) == 0 {
+				return nil, selection.insufficientError(asset, target)
+			}
+			selection.add(remaining[0])
+			remaining = remaining[1:]
+		}
+	}
+	return selection.selected, nil
+}
+
+// RandomImprove selects random inputs until the target is covered and then
+// improves the selection towards twice the target, so the change outputs are
+// similar to the payments. Rand is used as the source of randomness if provided.
+type RandomImprove struct {
+	Rand *rand.Rand
+}
+
+// Select implements CoinSelection.
+func (ri RandomImprove) Select(available []*TxInput, target *Value) ([]*TxInput, error) {
+	selection := newInputSelection(available)
+	assets := selectionAssets(target)
+
+	// Random selection phase
+	for _, asset := range assets {
+		remaining := selection.remaining(asset)
+		ri.shuffle(remaining)
+		for selection.quantity(asset) < asset.quantity(target) {
+			if len(remaining) == 0 {
+				return nil, selection.insufficientError(asset, target)
+			}
+			selection.add(remaining[0])
+			remaining = remaining[1:]
+		}
+	}
+
+	// Improvement phase, the ideal quantity is twice the target and the maximum three times
+	for _, asset := range assets {
+		ideal, max := 2*asset.quantity(target), 3*asset.quantity(target)
+		remaining := selection.remaining(asset)
+		ri.shuffle(remaining)
+		for _, input := range remaining {
+			current := selection.quantity(asset)
+			next := current + asset.quantity(input.Amount)
+			if next > max || distance(next, ideal) >= distance(current, ideal) {
+				break
+			}
+			selection.add(input)
+		}
+	}
+
+	return selection.selected, nil
+}
+
+func (ri RandomImprove) shuffle(inputs []*TxInput) {
+	swap := func(i, j int) { inputs[i], inputs[j] = inputs[j], inputs[i] }
+	if ri.Rand != nil {
+		ri.Rand.Shuffle(len(inputs), swap)
+	} else {
+		rand.Shuffle(len(inputs), swap)
+	}
+}
+
+func distance(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// selectionAsset is the coin or a native token of a Value.
+type selectionAsset struct {
+	coin     bool
+	policyID cbor.ByteString
+	name     cbor.ByteString
+}
+
+// quantity returns the amount of the asset in the value.
+func (a selectionAsset) quantity(v *Value) uint64 {
+	if v == nil {
+		return 0
+	}
+	if a.coin {
+		return uint64(v.Coin)
+	}
+	if v.MultiAsset == nil {
+		return 0
+	}
+	assets, ok := v.MultiAsset.m[a.policyID]
+	if !ok {
+		return 0
+	}
+	return uint64(assets.m[a.name])
+}
+
+// String implements Stringer.
+func (a selectionAsset) String() string {
+	if a.coin {
+		return "lovelace"
+	}
+	return fmt.Sprintf("%x.%x", a.policyID.Bytes(), a.name.Bytes())
+}
+
+// selectionAssets returns the non-zero assets of the value, the native tokens
+// are sorted and followed by the coin.
+func selectionAssets(v *Value) []selectionAsset {
+	assets := []selectionAsset{}
+	if v.MultiAsset != nil {
+		for policyID, policyAssets := range v.MultiAsset.m {
+			for name, quantity := range policyAssets.m {
+				if quantity > 0 {
+					assets = append(assets, selectionAsset{policyID: policyID, name: name})
+				}
+			}
+		}
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		if cmp := bytes.Compare(assets[i].policyID.Bytes(), assets[j].policyID.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+		return bytes.Compare(assets[i].name.Bytes(), assets[j].name.Bytes()) < 0
+	})
+	if v.Coin > 0 {
+		assets = append(assets, selectionAsset{coin: true})
+	}
+	return assets
+}
+
+// inputSelection tracks the selected inputs and their total value.
+type inputSelection struct {
+	available []*TxInput
+	selected  []*TxInput
+	amount    *Value
+	isUsed    map[*TxInput]bool
+}
+
+func newInputSelection(available []*TxInput) *inputSelection {
+	return &inputSelection{
+		available: available,
+		selected:  []*TxInput{},
+		amount:    NewValue(0),
+		isUsed:    make(map[*TxInput]bool),
+	}
+}
+
+func (s *inputSelection) add(input *TxInput) {
+	amount := input.Amount
+	if amount.MultiAsset == nil {
+		amount = NewValue(amount.Coin)
+	}
+	s.selected = append(s.selected, input)
+	s.amount = s.amount.Add(amount)
+	s.isUsed[input] = true
+}
+
+func (s *inputSelection) quantity(asset selectionAsset) uint64 {
+	return asset.quantity(s.amount)
+}
+
+// remaining returns the unselected inputs holding the asset.
+func (s *inputSelection) remaining(asset selectionAsset) []*TxInput {
+	remaining := []*TxInput{}
+	for _, input := range s.available {
+		if !s.isUsed[input] && asset.quantity(input.Amount) > 0 {
+			remaining = append(remaining, input)
+		}
+	}
+	return remaining
+}
+
+func (s *inputSelection) insufficientError(asset selectionAsset, target *Value) error {
+	var total uint64
+	for _, input := range s.available {
+		total += asset.quantity(input.Amount)
+	}
+	return fmt.Errorf(
+		"insufficient %v in available inputs, got %v want %v",
+		asset,
+		total,
+		asset.quantity(target),
+	)
+}
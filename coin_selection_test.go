@@ -0,0 +1,250 @@
+package cardano
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/echovl/cardano-go/crypto"
+)
+
+func newSelectionInputs(t *testing.T, amounts ...*Value) []*TxInput {
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := make([]*TxInput, len(amounts))
+	for i, amount := range amounts {
+		inputs[i] = NewTxInput(txHash, uint(i), amount)
+	}
+	return inputs
+}
+
+func selectedAmount(inputs []*TxInput) *Value {
+	amount := NewValue(0)
+	for _, input := range inputs {
+		amount = amount.Add(input.Amount)
+	}
+	return amount
+}
+
+func TestCoinSelection(t *testing.T) {
+	policyKey := crypto.NewXPrvKeyFromEntropy([]byte("policy"), "")
+	policyScript, err := NewScriptPubKey(policyKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID, err := NewPolicyID(policyScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetName := NewAssetName("cardanogo")
+	withAsset := func(coin Coin, quantity BigNum) *Value {
+		return NewValueWithAssets(coin, NewMultiAsset().Set(policyID, NewAssets().Set(assetName, quantity)))
+	}
+
+	inputs := newSelectionInputs(t,
+		NewValue(1e6),
+		NewValue(5e6),
+		NewValue(20e6),
+		withAsset(2e6, 10),
+		NewValue(3e6),
+		withAsset(1.5e6, 50),
+	)
+
+	testcases := []struct {
+		name      string
+		target    *Value
+		wantCount int // only checked for LargestFirst
+		wantErr   bool
+	}{
+		{name: "coin", target: NewValue(22e6), wantCount: 2},
+		{name: "asset", target: withAsset(1e6, 40), wantCount: 1},
+		{name: "asset and coin", target: withAsset(25e6, 55), wantCount: 4},
+		{name: "insufficient coin", target: NewValue(100e6), wantErr: true},
+		{name: "insufficient asset", target: withAsset(1e6, 61), wantErr: true},
+	}
+
+	selections := []struct {
+		name      string
+		selection CoinSelection
+	}{
+		{name: "LargestFirst", selection: LargestFirst{}},
+		{name: "RandomImprove", selection: RandomImprove{Rand: rand.New(rand.NewSource(1))}},
+	}
+
+	for _, s := range selections {
+		for _, tc := range testcases {
+			t.Run(s.name+" "+tc.name, func(t *testing.T) {
+				selected, err := s.selection.Select(inputs, tc.target)
+				if err != nil {
+					if tc.wantErr {
+						return
+					}
+					t.Fatal(err)
+				}
+				if tc.wantErr {
+					t.Fatal("expected error")
+				}
+				if got := selectedAmount(selected); got.Cmp(tc.target) == -1 || got.Cmp(tc.target) == 2 {
+					t.Errorf("selection doesn't cover the target\ngot: %v\nwant: %v", got, tc.target)
+				}
+				if _, ok := s.selection.(LargestFirst); ok && len(selected) != tc.wantCount {
+					t.Errorf("invalid number of inputs\ngot: %v\nwant: %v", len(selected), tc.wantCount)
+				}
+			})
+		}
+	}
+}
+
+func TestRandomImprove(t *testing.T) {
+	amounts := []*Value{}
+	for i := 0; i < 50; i++ {
+		amounts = append(amounts, NewValue(1e6))
+	}
+	inputs := newSelectionInputs(t, amounts...)
+
+	// The improvement phase moves the selection towards twice the target
+	selected, err := RandomImprove{Rand: rand.New(rand.NewSource(1))}.Select(inputs, NewValue(10e6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := selectedAmount(selected).Coin, Coin(20e6); got != want {
+		t.Errorf("invalid selection amount\ngot: %v\nwant: %v", got, want)
+	}
+}
+
+func TestTxBuilderSelectInputs(t *testing.T) {
+	paymentKey := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	otherKey := crypto.NewXPrvKeyFromEntropy([]byte("other"), "")
+	payment, err := NewKeyCredential(paymentKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewEnterpriseAddress(Testnet, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := newSelectionInputs(t, NewValue(1e6), NewValue(3e6), NewValue(10.1e6), NewValue(50e6))
+	for _, input := range inputs {
+		input.Address = &addr
+	}
+
+	testcases := []struct {
+		name      string
+		selection CoinSelection
+		sign      bool
+	}{
+		{name: "LargestFirst", selection: LargestFirst{}, sign: true},
+		{name: "RandomImprove", selection: RandomImprove{Rand: rand.New(rand.NewSource(1))}, sign: true},
+		{name: "Without keys", selection: LargestFirst{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(alonzoProtocol)
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(10e6)))
+			txBuilder.SelectInputs(tc.selection, inputs...)
+			txBuilder.AddChangeIfNeeded(addr)
+			if tc.sign {
+				txBuilder.SignInputs(otherKey.PrvKey(), paymentKey.PrvKey())
+			}
+
+			tx, err := txBuilder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inputAmount := selectedAmount(tx.Body.Inputs)
+			outputAmount := NewValue(tx.Body.Fee)
+			for _, output := range tx.Body.Outputs {
+				outputAmount = outputAmount.Add(output.Amount)
+			}
+			if inputAmount.Cmp(outputAmount) != 0 {
+				t.Errorf("unbalanced transaction\ngot: %v\nwant: %v", outputAmount, inputAmount)
+			}
+
+			// Only the key of the selected inputs signs the transaction
+			if got, want := len(tx.WitnessSet.VKeyWitnessSet), 1; tc.sign && got != want {
+				t.Errorf("invalid number of witnesses\ngot: %v\nwant: %v", got, want)
+			}
+
+			// The fee must cover the witness of the selected inputs
+			if !tc.sign {
+				txBuilder.Sign(paymentKey.PrvKey())
+				if err := txBuilder.build(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if minFee := txBuilder.calculateMinFee(); tx.Body.Fee < minFee {
+				t.Errorf("fee too small\ngot: %v\nwant: %v", tx.Body.Fee, minFee)
+			}
+		})
+	}
+
+	txBuilder := NewTxBuilder(alonzoProtocol)
+	txBuilder.AddOutputs(NewTxOutput(addr, NewValue(10e6)))
+	txBuilder.SelectInputs(LargestFirst{}, inputs...)
+	if _, err := txBuilder.Build(); err == nil {
+		t.Error("expected missing change address error")
+	}
+}
+
+func TestTxBuilderSelectInputsDustChange(t *testing.T) {
+	payment, err := NewKeyCredential(crypto.NewXPrvKeyFromEntropy([]byte("payment"), "").PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewEnterpriseAddress(Testnet, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name    string
+		inputs  []*TxInput
+		nInputs int
+		change  bool
+		fee     Coin
+	}{
+		{
+			name:    "extra input for change",
+			inputs:  newSelectionInputs(t, NewValue(10.3e6), NewValue(1.5e6)),
+			nInputs: 2,
+			change:  true,
+		},
+		{
+			name:    "change burned without more inputs",
+			inputs:  newSelectionInputs(t, NewValue(10.3e6)),
+			nInputs: 1,
+			fee:     0.3e6,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(alonzoProtocol)
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(10e6)))
+			txBuilder.SelectInputs(LargestFirst{}, tc.inputs...)
+			txBuilder.AddChangeIfNeeded(addr)
+
+			tx, err := txBuilder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(tx.Body.Inputs), tc.nInputs; got != want {
+				t.Errorf("invalid number of inputs\ngot: %v\nwant: %v", got, want)
+			}
+			if got, want := len(tx.Body.Outputs) == 2, tc.change; got != want {
+				t.Errorf("invalid change output\ngot: %v\nwant: %v", got, want)
+			}
+			if tc.change {
+				if minFee := txBuilder.calculateMinFee(); tx.Body.Fee != minFee {
+					t.Errorf("invalid fee\ngot: %v\nwant: %v", tx.Body.Fee, minFee)
+				}
+			} else if got, want := tx.Body.Fee, tc.fee; got != want {
+				t.Errorf("invalid fee\ngot: %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
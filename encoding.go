@@ -1,7 +1,9 @@
 package cardano
 
 import (
+	"bytes"
 	"fmt"
+	"math"
 
 	"github.com/echovl/cardano-go/internal/cbor"
 )
@@ -10,7 +12,7 @@ var cborEnc, _ = cbor.CanonicalEncOptions().EncMode()
 var cborDec, _ = cbor.DecOptions{MapKeyByteString: cbor.MapKeyByteStringWrap}.DecMode()
 
 func getTypeFromCBORArray(data []byte) (uint64, error) {
-	raw := []interface{}{}
+	raw := []cbor.RawMessage{}
 	if err := cborDec.Unmarshal(data, &raw); err != nil {
 		return 0, err
 	}
@@ -19,10 +21,136 @@ func getTypeFromCBORArray(data []byte) (uint64, error) {
 		return 0, fmt.Errorf("empty CBOR array")
 	}
 
-	t, ok := raw[0].(uint64)
-	if !ok {
+	var t uint64
+	if err := cborDec.Unmarshal(raw[0], &t); err != nil {
 		return 0, fmt.Errorf("invalid Type")
 	}
 
 	return t, nil
 }
+
+// encodeCBORHead encodes the head of a CBOR data item using the shortest
+// possible argument.
+func encodeCBORHead(major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return []byte{major | byte(n)}
+	case n <= math.MaxUint8:
+		return []byte{major | 24, byte(n)}
+	case n <= math.MaxUint16:
+		return []byte{major | 25, byte(n >> 8), byte(n)}
+	case n <= math.MaxUint32:
+		return []byte{major | 26, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	default:
+		return []byte{
+			major | 27,
+			byte(n >> 56), byte(n >> 48), byte(n >> 40), byte(n >> 32),
+			byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n),
+		}
+	}
+}
+
+// decodeCBORHead decodes the head of a CBOR data item and returns its major type,
+// its argument, the size of the head and whether the item has indefinite length.
+func decodeCBORHead(data []byte) (byte, uint64, int, bool, error) {
+	if len(data) == 0 {
+		return 0, 0, 0, false, fmt.Errorf("cbor: unexpected end of data")
+	}
+
+	major, ai := data[0]>>5, data[0]&0x1f
+	switch {
+	case ai < 24:
+		return major, uint64(ai), 1, false, nil
+	case ai <= 27:
+		size := 1 << (ai - 24)
+		if len(data) < 1+size {
+			return 0, 0, 0, false, fmt.Errorf("cbor: unexpected end of data")
+		}
+		var n uint64
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		return major, n, 1 + size, false, nil
+	case ai == 31 && major >= 2 && major <= 5:
+		return major, 0, 1, true, nil
+	default:
+		return 0, 0, 0, false, fmt.Errorf("cbor: invalid additional information %d", ai)
+	}
+}
+
+// getMapPairsFromCBOR returns the raw keys and values of a CBOR map in the order
+// they were encoded. It is useful for maps whose keys can't be used as Go map keys.
+func getMapPairsFromCBOR(data []byte) ([][2]cbor.RawMessage, error) {
+	major, n, off, indef, err := decodeCBORHead(data)
+	if err != nil {
+		return nil, err
+	}
+	if major != 5 {
+		return nil, fmt.Errorf("cbor: expected CBOR map got major type %d", major)
+	}
+
+	pairs := [][2]cbor.RawMessage{}
+	dec := cborDec.NewDecoder(bytes.NewReader(data[off:]))
+	for i := uint64(0); indef || i < n; i++ {
+		if indef {
+			if pos := off + dec.NumBytesRead(); pos >= len(data) {
+				return nil, fmt.Errorf("cbor: unexpected end of map")
+			} else if data[pos] == 0xff {
+				break
+			}
+		}
+		var pair [2]cbor.RawMessage
+		if err := dec.Decode(&pair[0]); err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(&pair[1]); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+// unwrapEncodedCBOR returns the content of an encoded CBOR data item (tag 24).
+func unwrapEncodedCBOR(data []byte) ([]byte, error) {
+	var tag cbor.RawTag
+	if err := tag.UnmarshalCBOR(data); err != nil {
+		return nil, err
+	}
+	if tag.Number != 24 {
+		return nil, fmt.Errorf("cbor: expected tag 24 got %d", tag.Number)
+	}
+	var content []byte
+	if err := cborDec.Unmarshal(tag.Content, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// originalCBOR keeps the original encoding of a decoded value when it differs
+// from the encoding produced by this package (e.g. non-canonical map ordering or
+// indefinite-length arrays), so it can be hashed and re-serialized byte-exactly.
+type originalCBOR struct {
+	raw     []byte // original encoding
+	encoded []byte // encoding of the value right after it was decoded
+}
+
+func newOriginalCBOR(raw, encoded []byte) originalCBOR {
+	if bytes.Equal(raw, encoded) {
+		return originalCBOR{}
+	}
+	original := originalCBOR{raw: make([]byte, len(raw)), encoded: encoded}
+	copy(original.raw, raw)
+	return original
+}
+
+// bytes returns the original encoding if the value wasn't modified since it
+// was decoded, otherwise it returns the given encoding.
+func (o *originalCBOR) bytes(encoded []byte) []byte {
+	if o.raw != nil && bytes.Equal(encoded, o.encoded) {
+		return o.raw
+	}
+	return encoded
+}
@@ -0,0 +1,501 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/echovl/cardano-go/internal/bech32"
+	"github.com/echovl/cardano-go/internal/cbor"
+)
+
+// CIP-129 header bytes of the DRep identifiers.
+const (
+	drepKeyHashHeader    = 0x22
+	drepScriptHashHeader = 0x23
+)
+
+type DRepType uint64
+
+const (
+	DRepKeyHash DRepType = iota
+	DRepScriptHash
+	DRepAlwaysAbstain
+	DRepAlwaysNoConfidence
+)
+
+// DRep is a delegated representative used for vote delegation.
+type DRep struct {
+	Type       DRepType
+	KeyHash    AddrKeyHash
+	ScriptHash Hash28
+}
+
+// NewDRep returns the DRep of a DRep credential.
+func NewDRep(cred StakeCredential) DRep {
+	if cred.Type == ScriptCredential {
+		return DRep{Type: DRepScriptHash, ScriptHash: cred.ScriptHash}
+	}
+	return DRep{Type: DRepKeyHash, KeyHash: cred.KeyHash}
+}
+
+// NewDRepFromBech32 creates a DRep from a bech32 encoded DRep id.
+// Both CIP-129 and CIP-105 (drep, drep_script) identifiers are supported.
+func NewDRepFromBech32(bech string) (DRep, error) {
+	hrp, data, err := bech32.DecodeToBase256(bech)
+	if err != nil {
+		return DRep{}, err
+	}
+
+	switch {
+	case hrp == "drep" && len(data) == 29:
+		switch data[0] {
+		case drepKeyHashHeader:
+			return DRep{Type: DRepKeyHash, KeyHash: data[1:]}, nil
+		case drepScriptHashHeader:
+			return DRep{Type: DRepScriptHash, ScriptHash: data[1:]}, nil
+		default:
+			return DRep{}, fmt.Errorf("invalid drep id header %x", data[0])
+		}
+	case hrp == "drep" && len(data) == 28:
+		return DRep{Type: DRepKeyHash, KeyHash: data}, nil
+	case hrp == "drep_script" && len(data) == 28:
+		return DRep{Type: DRepScriptHash, ScriptHash: data}, nil
+	default:
+		return DRep{}, fmt.Errorf("invalid drep id %s", bech)
+	}
+}
+
+// Bech32 returns the CIP-129 DRep id encoded as bech32.
+func (d *DRep) Bech32() (string, error) {
+	var data []byte
+	switch d.Type {
+	case DRepKeyHash:
+		data = append([]byte{drepKeyHashHeader}, d.KeyHash...)
+	case DRepScriptHash:
+		data = append([]byte{drepScriptHashHeader}, d.ScriptHash...)
+	default:
+		return "", fmt.Errorf("drep type %d has no id", d.Type)
+	}
+	return bech32.EncodeFromBase256("drep", data)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (d *DRep) MarshalCBOR() ([]byte, error) {
+	var drep []interface{}
+	switch d.Type {
+	case DRepKeyHash:
+		drep = append(drep, d.Type, d.KeyHash)
+	case DRepScriptHash:
+		drep = append(drep, d.Type, d.ScriptHash)
+	default:
+		drep = append(drep, d.Type)
+	}
+
+	return cborEnc.Marshal(drep)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (d *DRep) UnmarshalCBOR(data []byte) error {
+	var drep []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &drep); err != nil {
+		return err
+	}
+	if len(drep) == 0 {
+		return fmt.Errorf("cbor: cannot unmarshal empty CBOR array into DRep")
+	}
+
+	var drepType DRepType
+	if err := cborDec.Unmarshal(drep[0], &drepType); err != nil {
+		return err
+	}
+
+	switch drepType {
+	case DRepKeyHash, DRepScriptHash:
+		if len(drep) != 2 {
+			return fmt.Errorf("cbor: invalid DRep, expected 2 elements got %d", len(drep))
+		}
+		var hash Hash28
+		if err := cborDec.Unmarshal(drep[1], &hash); err != nil {
+			return err
+		}
+		if drepType == DRepKeyHash {
+			d.KeyHash = hash
+		} else {
+			d.ScriptHash = hash
+		}
+	case DRepAlwaysAbstain, DRepAlwaysNoConfidence:
+	default:
+		return fmt.Errorf("cbor: invalid DRep type %d", drepType)
+	}
+	d.Type = drepType
+
+	return nil
+}
+
+// Anchor is a reference to off-chain metadata.
+type Anchor struct {
+	_        struct{} `cbor:",toarray"`
+	URL      string
+	DataHash Hash32
+}
+
+// GovActionID is the identifier of a governance action.
+type GovActionID struct {
+	_      struct{} `cbor:",toarray"`
+	TxHash Hash32
+	Index  uint64
+}
+
+// NewGovActionIDFromBech32 creates a GovActionID from a CIP-129 bech32 encoded id.
+func NewGovActionIDFromBech32(bech string) (GovActionID, error) {
+	hrp, data, err := bech32.DecodeToBase256(bech)
+	if err != nil {
+		return GovActionID{}, err
+	}
+	if hrp != "gov_action" {
+		return GovActionID{}, fmt.Errorf("invalid gov action id prefix %s", hrp)
+	}
+
+	switch len(data) {
+	case 33:
+		return GovActionID{TxHash: data[:32], Index: uint64(data[32])}, nil
+	case 34:
+		return GovActionID{TxHash: data[:32], Index: uint64(binary.BigEndian.Uint16(data[32:]))}, nil
+	default:
+		return GovActionID{}, fmt.Errorf("invalid gov action id length %d", len(data))
+	}
+}
+
+// Bech32 returns the CIP-129 governance action id encoded as bech32.
+func (id *GovActionID) Bech32() string {
+	data := append([]byte{}, id.TxHash...)
+	if id.Index <= 0xff {
+		data = append(data, byte(id.Index))
+	} else {
+		index := make([]byte, 2)
+		binary.BigEndian.PutUint16(index, uint16(id.Index))
+		data = append(data, index...)
+	}
+	bech, err := bech32.EncodeFromBase256("gov_action", data)
+	if err != nil {
+		panic(err)
+	}
+	return bech
+}
+
+type VoterType uint64
+
+const (
+	CommitteeHotKeyVoter VoterType = iota
+	CommitteeHotScriptVoter
+	DRepKeyVoter
+	DRepScriptVoter
+	StakePoolVoter
+)
+
+// Voter is a governance voter, identified by its key hash or script hash.
+type Voter struct {
+	_    struct{} `cbor:",toarray"`
+	Type VoterType
+	Hash Hash28
+}
+
+type Vote uint64
+
+const (
+	VoteNo Vote = iota
+	VoteYes
+	VoteAbstain
+)
+
+// VotingProcedure is a vote with an optional anchor.
+type VotingProcedure struct {
+	_      struct{} `cbor:",toarray"`
+	Vote   Vote
+	Anchor *Anchor // or null
+}
+
+// GovActionVote is the vote of a voter for a governance action.
+type GovActionVote struct {
+	GovActionID GovActionID
+	Procedure   VotingProcedure
+}
+
+// VoterVotes are the votes cast by a voter.
+type VoterVotes struct {
+	Voter Voter
+	Votes []GovActionVote
+}
+
+// VotingProcedures are the votes cast by each voter.
+// Voters and votes keep their insertion order when encoded.
+type VotingProcedures []VoterVotes
+
+// Add adds a vote of the voter for the governance action.
+func (vp *VotingProcedures) Add(voter Voter, id GovActionID, procedure VotingProcedure) {
+	vote := GovActionVote{GovActionID: id, Procedure: procedure}
+	for i, vv := range *vp {
+		if vv.Voter.Type == voter.Type && bytes.Equal(vv.Voter.Hash, voter.Hash) {
+			(*vp)[i].Votes = append((*vp)[i].Votes, vote)
+			return
+		}
+	}
+	*vp = append(*vp, VoterVotes{Voter: voter, Votes: []GovActionVote{vote}})
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (vp VotingProcedures) MarshalCBOR() ([]byte, error) {
+	out := encodeCBORHead(5, uint64(len(vp)))
+	for _, vv := range vp {
+		voter, err := cborEnc.Marshal(vv.Voter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, voter...)
+		out = append(out, encodeCBORHead(5, uint64(len(vv.Votes)))...)
+		for _, vote := range vv.Votes {
+			id, err := cborEnc.Marshal(vote.GovActionID)
+			if err != nil {
+				return nil, err
+			}
+			procedure, err := cborEnc.Marshal(vote.Procedure)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, id...)
+			out = append(out, procedure...)
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (vp *VotingProcedures) UnmarshalCBOR(data []byte) error {
+	pairs, err := getMapPairsFromCBOR(data)
+	if err != nil {
+		return err
+	}
+	procedures := make(VotingProcedures, len(pairs))
+	for i, pair := range pairs {
+		if err := cborDec.Unmarshal(pair[0], &procedures[i].Voter); err != nil {
+			return err
+		}
+		votes, err := getMapPairsFromCBOR(pair[1])
+		if err != nil {
+			return err
+		}
+		procedures[i].Votes = make([]GovActionVote, len(votes))
+		for j, vote := range votes {
+			if err := cborDec.Unmarshal(vote[0], &procedures[i].Votes[j].GovActionID); err != nil {
+				return err
+			}
+			if err := cborDec.Unmarshal(vote[1], &procedures[i].Votes[j].Procedure); err != nil {
+				return err
+			}
+		}
+	}
+	*vp = procedures
+	return nil
+}
+
+// ProposalProcedure is a governance action proposal.
+type ProposalProcedure struct {
+	_             struct{} `cbor:",toarray"`
+	Deposit       Coin
+	RewardAccount Address
+	GovAction     GovAction
+	Anchor        Anchor
+}
+
+type GovActionType uint64
+
+const (
+	ParameterChangeAction GovActionType = iota
+	HardForkInitiationAction
+	TreasuryWithdrawalsAction
+	NoConfidenceAction
+	UpdateCommitteeAction
+	NewConstitutionAction
+	InfoAction
+)
+
+// CommitteeMember is a constitutional committee member and its expiration epoch.
+type CommitteeMember struct {
+	ColdCredential StakeCredential
+	Epoch          uint64
+}
+
+// Constitution is the Cardano constitution.
+type Constitution struct {
+	_          struct{} `cbor:",toarray"`
+	Anchor     Anchor
+	ScriptHash Hash28 // or null
+}
+
+// GovAction is a governance action.
+type GovAction struct {
+	Type GovActionType
+
+	// Common fields
+	PrevActionID *GovActionID // or null
+	PolicyHash   Hash28       // or null
+
+	// Parameter change fields
+	ProtocolParamUpdate ProtocolParamUpdate
+
+	// Hard fork initiation fields
+	ProtocolVersion ProtocolVersion
+
+	// Treasury withdrawals fields
+	Withdrawals *Withdrawals
+
+	// Update committee fields
+	RemovedMembers []StakeCredential
+	AddedMembers   []CommitteeMember
+	Quorum         UnitInterval
+
+	// New constitution fields
+	Constitution Constitution
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (g *GovAction) MarshalCBOR() ([]byte, error) {
+	var action []interface{}
+	switch g.Type {
+	case ParameterChangeAction:
+		action = append(action, g.Type, g.PrevActionID, g.ProtocolParamUpdate, g.PolicyHash)
+	case HardForkInitiationAction:
+		action = append(action, g.Type, g.PrevActionID, g.ProtocolVersion)
+	case TreasuryWithdrawalsAction:
+		withdrawals := g.Withdrawals
+		if withdrawals == nil {
+			withdrawals = NewWithdrawals()
+		}
+		action = append(action, g.Type, withdrawals, g.PolicyHash)
+	case NoConfidenceAction:
+		action = append(action, g.Type, g.PrevActionID)
+	case UpdateCommitteeAction:
+		removed := g.RemovedMembers
+		if removed == nil {
+			removed = []StakeCredential{}
+		}
+		added := encodeCBORHead(5, uint64(len(g.AddedMembers)))
+		for _, member := range g.AddedMembers {
+			cred, err := member.ColdCredential.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			added = append(added, cred...)
+			added = append(added, encodeCBORHead(0, member.Epoch)...)
+		}
+		action = append(action, g.Type, g.PrevActionID, removed, cbor.RawMessage(added), g.Quorum)
+	case NewConstitutionAction:
+		action = append(action, g.Type, g.PrevActionID, g.Constitution)
+	case InfoAction:
+		action = append(action, g.Type)
+	default:
+		return nil, fmt.Errorf("cbor: invalid GovAction type %d", g.Type)
+	}
+
+	return cborEnc.Marshal(action)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (g *GovAction) UnmarshalCBOR(data []byte) error {
+	var action []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &action); err != nil {
+		return err
+	}
+	if len(action) == 0 {
+		return fmt.Errorf("cbor: cannot unmarshal empty CBOR array into GovAction")
+	}
+
+	var actionType GovActionType
+	if err := cborDec.Unmarshal(action[0], &actionType); err != nil {
+		return err
+	}
+
+	wantLen := map[GovActionType]int{
+		ParameterChangeAction:     4,
+		HardForkInitiationAction:  3,
+		TreasuryWithdrawalsAction: 3,
+		NoConfidenceAction:        2,
+		UpdateCommitteeAction:     5,
+		NewConstitutionAction:     3,
+		InfoAction:                1,
+	}
+	n, ok := wantLen[actionType]
+	if !ok {
+		return fmt.Errorf("cbor: invalid GovAction type %d", actionType)
+	}
+	if len(action) != n {
+		return fmt.Errorf("cbor: invalid GovAction, expected %d elements got %d", n, len(action))
+	}
+
+	*g = GovAction{Type: actionType}
+	switch actionType {
+	case ParameterChangeAction:
+		if err := cborDec.Unmarshal(action[1], &g.PrevActionID); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[2], &g.ProtocolParamUpdate); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[3], &g.PolicyHash); err != nil {
+			return err
+		}
+	case HardForkInitiationAction:
+		if err := cborDec.Unmarshal(action[1], &g.PrevActionID); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[2], &g.ProtocolVersion); err != nil {
+			return err
+		}
+	case TreasuryWithdrawalsAction:
+		g.Withdrawals = NewWithdrawals()
+		if err := cborDec.Unmarshal(action[1], g.Withdrawals); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[2], &g.PolicyHash); err != nil {
+			return err
+		}
+	case NoConfidenceAction:
+		if err := cborDec.Unmarshal(action[1], &g.PrevActionID); err != nil {
+			return err
+		}
+	case UpdateCommitteeAction:
+		if err := cborDec.Unmarshal(action[1], &g.PrevActionID); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[2], &g.RemovedMembers); err != nil {
+			return err
+		}
+		members, err := getMapPairsFromCBOR(action[3])
+		if err != nil {
+			return err
+		}
+		for _, pair := range members {
+			member := CommitteeMember{}
+			if err := cborDec.Unmarshal(pair[0], &member.ColdCredential); err != nil {
+				return err
+			}
+			if err := cborDec.Unmarshal(pair[1], &member.Epoch); err != nil {
+				return err
+			}
+			g.AddedMembers = append(g.AddedMembers, member)
+		}
+		if err := cborDec.Unmarshal(action[4], &g.Quorum); err != nil {
+			return err
+		}
+	case NewConstitutionAction:
+		if err := cborDec.Unmarshal(action[1], &g.PrevActionID); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(action[2], &g.Constitution); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/bech32"
+)
+
+func TestDRepBech32(t *testing.T) {
+	keyHash, _ := hex.DecodeString("8f4c16a4b2bd3b1b81a4a7b4e4d0b6dd20d1f2cd1e4e0e7d85a25a4e")
+	drep := DRep{Type: DRepKeyHash, KeyHash: keyHash}
+
+	id, err := drep.Bech32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, data, err := bech32.DecodeToBase256(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hrp != "drep" || data[0] != 0x22 {
+		t.Errorf("invalid drep id: %s", id)
+	}
+	decoded, err := NewDRepFromBech32(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, drep) {
+		t.Errorf("invalid drep\ngot: %+v\nwant: %+v", decoded, drep)
+	}
+
+	// CIP-105 identifiers
+	legacyID, err := bech32.EncodeFromBase256("drep", keyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = NewDRepFromBech32(legacyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, drep) {
+		t.Errorf("invalid drep\ngot: %+v\nwant: %+v", decoded, drep)
+	}
+
+	scriptID, err := bech32.EncodeFromBase256("drep_script", keyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = NewDRepFromBech32(scriptID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (DRep{Type: DRepScriptHash, ScriptHash: keyHash}); !reflect.DeepEqual(decoded, want) {
+		t.Errorf("invalid drep\ngot: %+v\nwant: %+v", decoded, want)
+	}
+
+	if _, err := (&DRep{Type: DRepAlwaysAbstain}).Bech32(); err == nil {
+		t.Errorf("expected error for always abstain drep")
+	}
+}
+
+func TestGovActionIDBech32(t *testing.T) {
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []uint64{0, 17, 300} {
+		id := GovActionID{TxHash: txHash, Index: index}
+		bech := id.Bech32()
+		if !strings.HasPrefix(bech, "gov_action1") {
+			t.Errorf("invalid gov action id prefix: %s", bech)
+		}
+		decoded, err := NewGovActionIDFromBech32(bech)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, id) {
+			t.Errorf("invalid gov action id\ngot: %+v\nwant: %+v", decoded, id)
+		}
+	}
+}
+
+func TestVotingProceduresEncoding(t *testing.T) {
+	hash := make(Hash28, 28)
+	txHash := make(Hash32, 32)
+
+	vp := VotingProcedures{}
+	vp.Add(Voter{Type: DRepKeyVoter, Hash: hash}, GovActionID{TxHash: txHash, Index: 0}, VotingProcedure{Vote: VoteYes})
+	vp.Add(Voter{Type: DRepKeyVoter, Hash: hash}, GovActionID{TxHash: txHash, Index: 1}, VotingProcedure{Vote: VoteNo})
+
+	if got, want := len(vp), 1; got != want {
+		t.Fatalf("invalid number of voters\ngot: %v\nwant: %v", got, want)
+	}
+
+	got, err := vp.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a1" + "8202581c00000000000000000000000000000000000000000000000000000000" +
+		"a2" +
+		"825820000000000000000000000000000000000000000000000000000000000000000000" + "8201f6" +
+		"825820000000000000000000000000000000000000000000000000000000000000000001" + "8200f6"
+	if hex.EncodeToString(got) != want {
+		t.Errorf("invalid voting procedures encoding\ngot: %x\nwant: %s", got, want)
+	}
+
+	decoded := VotingProcedures{}
+	if err := decoded.UnmarshalCBOR(got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, vp) {
+		t.Errorf("invalid voting procedures decoding\ngot: %+v\nwant: %+v", decoded, vp)
+	}
+}
+
+func TestGovActionEncoding(t *testing.T) {
+	hash := make(Hash28, 28)
+	txHash := make(Hash32, 32)
+	rewardAddr := Address{Network: Testnet, Type: Reward, Stake: StakeCredential{Type: KeyCredential, KeyHash: hash}}
+	prev := &GovActionID{TxHash: txHash, Index: 2}
+	anchor := Anchor{URL: "https://a.io", DataHash: txHash}
+	minFeeA := Coin(44)
+
+	testcases := []struct {
+		name   string
+		action GovAction
+	}{
+		{
+			name:   "parameter change",
+			action: GovAction{Type: ParameterChangeAction, PrevActionID: prev, ProtocolParamUpdate: ProtocolParamUpdate{MinFeeA: &minFeeA}},
+		},
+		{
+			name:   "hard fork initiation",
+			action: GovAction{Type: HardForkInitiationAction, ProtocolVersion: ProtocolVersion{Major: 10}},
+		},
+		{
+			name: "treasury withdrawals",
+			action: GovAction{
+				Type:        TreasuryWithdrawalsAction,
+				Withdrawals: NewWithdrawals().Set(rewardAddr, 1e9),
+				PolicyHash:  hash,
+			},
+		},
+		{
+			name:   "no confidence",
+			action: GovAction{Type: NoConfidenceAction, PrevActionID: prev},
+		},
+		{
+			name: "update committee",
+			action: GovAction{
+				Type:           UpdateCommitteeAction,
+				RemovedMembers: []StakeCredential{{Type: ScriptCredential, ScriptHash: hash}},
+				AddedMembers:   []CommitteeMember{{ColdCredential: StakeCredential{Type: KeyCredential, KeyHash: hash}, Epoch: 500}},
+				Quorum:         UnitInterval{P: 2, Q: 3},
+			},
+		},
+		{
+			name:   "new constitution",
+			action: GovAction{Type: NewConstitutionAction, Constitution: Constitution{Anchor: anchor, ScriptHash: hash}},
+		},
+		{
+			name:   "info",
+			action: GovAction{Type: InfoAction},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			proposal := ProposalProcedure{Deposit: 1e11, RewardAccount: rewardAddr, GovAction: tc.action, Anchor: anchor}
+			bytes, err := cborEnc.Marshal(proposal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := ProposalProcedure{}
+			if err := cborDec.Unmarshal(bytes, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, proposal) {
+				t.Errorf("invalid proposal decoding\ngot: %+v\nwant: %+v", decoded, proposal)
+			}
+		})
+	}
+
+	info, err := (&GovAction{Type: InfoAction}).MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(info), "8106"; got != want {
+		t.Errorf("invalid info action encoding\ngot: %s\nwant: %s", got, want)
+	}
+}
+
+func TestGovernanceBuild(t *testing.T) {
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	drepKey := crypto.NewXPrvKeyFromEntropy([]byte("drep"), "")
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drepCert, err := NewDRepRegistrationCertificate(drepKey.PubKey(), 5e8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputAmount, donation := Coin(1e9), Coin(1e6)
+
+	txBuilder := NewTxBuilder(alonzoProtocol)
+	txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(inputAmount)))
+	txBuilder.AddCertificate(drepCert)
+	txBuilder.AddVote(
+		Voter{Type: DRepKeyVoter, Hash: drepCert.DRepCredential.KeyHash},
+		GovActionID{TxHash: txHash, Index: 0},
+		VotingProcedure{Vote: VoteYes},
+	)
+	txBuilder.SetDonation(donation)
+	txBuilder.AddChangeIfNeeded(addr)
+	txBuilder.Sign(key.PrvKey(), drepKey.PrvKey())
+
+	tx, err := txBuilder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tx.Body.Outputs[0].Amount.Coin+tx.Body.Fee+drepCert.Deposit+donation, inputAmount; got != want {
+		t.Errorf("invalid tx balance\ngot: %v\nwant: %v", got, want)
+	}
+
+	gotTx := &Tx{}
+	if err := gotTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotTx.Body.VotingProcedures, tx.Body.VotingProcedures) {
+		t.Errorf("invalid voting procedures\ngot: %+v\nwant: %+v", gotTx.Body.VotingProcedures, tx.Body.VotingProcedures)
+	}
+	if got, want := gotTx.Body.Donation, donation; got != want {
+		t.Errorf("invalid donation\ngot: %v\nwant: %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+// Package base58 implements the base58 encoding used by Byron addresses,
+// which uses the Bitcoin alphabet.
+package base58
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	bigRadix = big.NewInt(58)
+	bigZero  = big.NewInt(0)
+
+	decodeMap [256]int8
+)
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = int8(i)
+	}
+}
+
+// ErrInvalidCharacter is returned when the base58 string contains a character
+// outside the alphabet.
+type ErrInvalidCharacter rune
+
+func (e ErrInvalidCharacter) Error() string {
+	return fmt.Sprintf("invalid base58 character: '%c'", rune(e))
+}
+
+// Encode encodes data as a base58 string.
+func Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+
+	out := make([]byte, 0, len(data)*138/100+1)
+	for x.Cmp(bigZero) > 0 {
+		x.DivMod(x, bigRadix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	// Leading zero bytes are encoded as the first character of the alphabet.
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+
+	// reverse
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+// Decode decodes a base58 string.
+func Decode(str string) ([]byte, error) {
+	x := new(big.Int)
+	for i := 0; i < len(str); i++ {
+		d := decodeMap[str[i]]
+		if d == -1 {
+			return nil, ErrInvalidCharacter(str[i])
+		}
+		x.Mul(x, bigRadix)
+		x.Add(x, big.NewInt(int64(d)))
+	}
+
+	var zeros int
+	for zeros < len(str) && str[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	decoded := x.Bytes()
+	out := make([]byte, zeros+len(decoded))
+	copy(out[zeros:], decoded)
+
+	return out, nil
+}
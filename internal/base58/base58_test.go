@@ -0,0 +1,45 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		hex string
+		str string
+	}{
+		{"", ""},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+		{"572e4794", "3EFU7m"},
+		{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+		{"10c8511e", "Rt5zm"},
+		{"00000000000000000000", "1111111111"},
+	}
+
+	for _, tc := range tests {
+		data, _ := hex.DecodeString(tc.hex)
+		if got := Encode(data); got != tc.str {
+			t.Errorf("invalid encoding\ngot: %v\nwant: %v", got, tc.str)
+		}
+		got, err := Decode(tc.str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("invalid decoding\ngot: %x\nwant: %x", got, data)
+		}
+	}
+
+	if _, err := Decode("0OIl"); err != ErrInvalidCharacter('0') {
+		t.Errorf("invalid error\ngot: %v\nwant: %v", err, ErrInvalidCharacter('0'))
+	}
+}
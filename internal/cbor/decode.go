@@ -731,7 +731,6 @@ func (d *decoder) parseToValue(v reflect.Value, tInfo *typeInfo) error { //nolin
 		} else {
 			return fillByteString(t, b, v)
 		}
-		return fillByteString(t, b, v)
 	case cborTypeTextString:
 		b, err := d.parseTextString()
 		if err != nil {
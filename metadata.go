@@ -0,0 +1,664 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// metadatumMaxSize is the maximum size in bytes of a metadata bytestring or text.
+const metadatumMaxSize = 64
+
+type TransactionMetadatumType uint8
+
+const (
+	MetadatumInt TransactionMetadatumType = iota
+	MetadatumBytes
+	MetadatumText
+	MetadatumList
+	MetadatumMap
+)
+
+// TransactionMetadatum is a typed value of the transaction metadata.
+type TransactionMetadatum struct {
+	Type TransactionMetadatumType
+
+	Int   *big.Int
+	Bytes []byte
+	Text  string
+	List  []TransactionMetadatum
+	Map   []TransactionMetadatumPair
+}
+
+// TransactionMetadatumPair is a key-value pair of a metadata Map.
+type TransactionMetadatumPair struct {
+	Key   TransactionMetadatum
+	Value TransactionMetadatum
+}
+
+// NewMetadatumInt returns a new Int metadatum.
+func NewMetadatumInt(n int64) TransactionMetadatum {
+	return NewMetadatumBigInt(big.NewInt(n))
+}
+
+// NewMetadatumBigInt returns a new Int metadatum from a big.Int.
+func NewMetadatumBigInt(n *big.Int) TransactionMetadatum {
+	return TransactionMetadatum{Type: MetadatumInt, Int: n}
+}
+
+// NewMetadatumBytes returns a new Bytes metadatum. Bytestrings larger than 64 bytes
+// are split into a List of 64 bytes chunks.
+func NewMetadatumBytes(b []byte) TransactionMetadatum {
+	if len(b) <= metadatumMaxSize {
+		return TransactionMetadatum{Type: MetadatumBytes, Bytes: b}
+	}
+	var chunks []TransactionMetadatum
+	for len(b) > 0 {
+		size := metadatumMaxSize
+		if len(b) < size {
+			size = len(b)
+		}
+		chunks = append(chunks, TransactionMetadatum{Type: MetadatumBytes, Bytes: b[:size]})
+		b = b[size:]
+	}
+	return NewMetadatumList(chunks...)
+}
+
+// NewMetadatumText returns a new Text metadatum. Texts larger than 64 bytes are
+// split into a List of texts of at most 64 bytes, without splitting UTF-8 characters.
+func NewMetadatumText(s string) TransactionMetadatum {
+	if len(s) <= metadatumMaxSize {
+		return TransactionMetadatum{Type: MetadatumText, Text: s}
+	}
+	var chunks []TransactionMetadatum
+	for len(s) > 0 {
+		size := metadatumMaxSize
+		if len(s) < size {
+			size = len(s)
+		}
+		for size < len(s) && size > 0 && !utf8.RuneStart(s[size]) {
+			size--
+		}
+		if size == 0 {
+			// Invalid UTF-8, split at the size limit
+			size = metadatumMaxSize
+		}
+		chunks = append(chunks, TransactionMetadatum{Type: MetadatumText, Text: s[:size]})
+		s = s[size:]
+	}
+	return NewMetadatumList(chunks...)
+}
+
+// NewMetadatumList returns a new List metadatum.
+func NewMetadatumList(items ...TransactionMetadatum) TransactionMetadatum {
+	return TransactionMetadatum{Type: MetadatumList, List: items}
+}
+
+// NewMetadatumMap returns a new Map metadatum.
+func NewMetadatumMap(pairs ...TransactionMetadatumPair) TransactionMetadatum {
+	return TransactionMetadatum{Type: MetadatumMap, Map: pairs}
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (md *TransactionMetadatum) MarshalCBOR() ([]byte, error) {
+	switch md.Type {
+	case MetadatumInt:
+		n := md.Int
+		if n == nil {
+			n = new(big.Int)
+		}
+		if n.Sign() >= 0 {
+			if !n.IsUint64() {
+				return nil, fmt.Errorf("metadata integer %v out of range", n)
+			}
+			return encodeCBORHead(0, n.Uint64()), nil
+		}
+		// Negative integers are encoded as -1 - n
+		abs := new(big.Int).Neg(n)
+		abs.Sub(abs, big.NewInt(1))
+		if !abs.IsUint64() {
+			return nil, fmt.Errorf("metadata integer %v out of range", n)
+		}
+		return encodeCBORHead(1, abs.Uint64()), nil
+	case MetadatumBytes:
+		if len(md.Bytes) > metadatumMaxSize {
+			return nil, fmt.Errorf("metadata bytestring larger than %d bytes", metadatumMaxSize)
+		}
+		return append(encodeCBORHead(2, uint64(len(md.Bytes))), md.Bytes...), nil
+	case MetadatumText:
+		if len(md.Text) > metadatumMaxSize {
+			return nil, fmt.Errorf("metadata text larger than %d bytes", metadatumMaxSize)
+		}
+		return append(encodeCBORHead(3, uint64(len(md.Text))), md.Text...), nil
+	case MetadatumList:
+		bytes := encodeCBORHead(4, uint64(len(md.List)))
+		for _, item := range md.List {
+			itemBytes, err := item.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			bytes = append(bytes, itemBytes...)
+		}
+		return bytes, nil
+	case MetadatumMap:
+		bytes := encodeCBORHead(5, uint64(len(md.Map)))
+		for _, pair := range md.Map {
+			key, err := pair.Key.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			value, err := pair.Value.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			bytes = append(bytes, key...)
+			bytes = append(bytes, value...)
+		}
+		return bytes, nil
+	default:
+		return nil, fmt.Errorf("cbor: invalid TransactionMetadatum type %d", md.Type)
+	}
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (md *TransactionMetadatum) UnmarshalCBOR(data []byte) error {
+	major, arg, _, _, err := decodeCBORHead(data)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case 0, 1:
+		n := new(big.Int).SetUint64(arg)
+		if major == 1 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		*md = NewMetadatumBigInt(n)
+	case 2:
+		var b []byte
+		if err := cborDec.Unmarshal(data, &b); err != nil {
+			return err
+		}
+		if len(b) > metadatumMaxSize {
+			return fmt.Errorf("metadata bytestring larger than %d bytes", metadatumMaxSize)
+		}
+		*md = TransactionMetadatum{Type: MetadatumBytes, Bytes: b}
+	case 3:
+		var s string
+		if err := cborDec.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if len(s) > metadatumMaxSize {
+			return fmt.Errorf("metadata text larger than %d bytes", metadatumMaxSize)
+		}
+		*md = TransactionMetadatum{Type: MetadatumText, Text: s}
+	case 4:
+		var items []TransactionMetadatum
+		if err := cborDec.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			items = nil
+		}
+		*md = NewMetadatumList(items...)
+	case 5:
+		pairs, err := getMapPairsFromCBOR(data)
+		if err != nil {
+			return err
+		}
+		m := make([]TransactionMetadatumPair, len(pairs))
+		for i, pair := range pairs {
+			if err := m[i].Key.UnmarshalCBOR(pair[0]); err != nil {
+				return err
+			}
+			if err := m[i].Value.UnmarshalCBOR(pair[1]); err != nil {
+				return err
+			}
+		}
+		*md = NewMetadatumMap(m...)
+	default:
+		return fmt.Errorf("cbor: cannot unmarshal CBOR major type %d into TransactionMetadatum", major)
+	}
+
+	return nil
+}
+
+// Metadatum returns the metadata value of the given label as a TransactionMetadatum.
+func (m Metadata) Metadatum(label uint) (TransactionMetadatum, error) {
+	value, ok := m[label]
+	if !ok {
+		return TransactionMetadatum{}, fmt.Errorf("metadata label %d not found", label)
+	}
+	switch v := value.(type) {
+	case TransactionMetadatum:
+		return v, nil
+	case *TransactionMetadatum:
+		return *v, nil
+	}
+
+	// Untyped values are converted using their CBOR encoding
+	bytes, err := cborEnc.Marshal(value)
+	if err != nil {
+		return TransactionMetadatum{}, err
+	}
+	var md TransactionMetadatum
+	if err := md.UnmarshalCBOR(bytes); err != nil {
+		return TransactionMetadatum{}, err
+	}
+
+	return md, nil
+}
+
+// MetadataJSONSchema is the JSON schema used by cardano-cli to represent metadata.
+type MetadataJSONSchema uint8
+
+const (
+	// MetadataJSONNoSchema maps JSON values to metadata values directly, strings
+	// prefixed by 0x are bytestrings.
+	MetadataJSONNoSchema MetadataJSONSchema = iota
+	// MetadataJSONDetailedSchema uses single-key objects (int, bytes, string, list
+	// and map) to describe the metadata values.
+	MetadataJSONDetailedSchema
+)
+
+// NewMetadataFromJSON returns a new Metadata from its cardano-cli JSON representation.
+// Long strings and bytestrings are split into lists.
+func NewMetadataFromJSON(data []byte, schema MetadataJSONSchema) (Metadata, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("metadata JSON must be an object")
+	}
+
+	metadata := Metadata{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		label, err := strconv.ParseUint(tok.(string), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata label %q", tok)
+		}
+		value, err := decodeMetadatumJSON(dec, schema)
+		if err != nil {
+			return nil, err
+		}
+		metadata[uint(label)] = value
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after metadata JSON")
+	}
+
+	return metadata, nil
+}
+
+// JSON returns the cardano-cli JSON representation of the metadata.
+func (m Metadata) JSON(schema MetadataJSONSchema) ([]byte, error) {
+	labels := make([]uint, 0, len(m))
+	for label := range m {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, label := range labels {
+		md, err := m.Metadatum(label)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(&buf, strconv.FormatUint(uint64(label), 10))
+		buf.WriteByte(':')
+		if err := md.writeJSON(&buf, schema); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// NewTransactionMetadatumFromJSON returns a new TransactionMetadatum from its
+// cardano-cli JSON representation.
+func NewTransactionMetadatumFromJSON(data []byte, schema MetadataJSONSchema) (TransactionMetadatum, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	md, err := decodeMetadatumJSON(dec, schema)
+	if err != nil {
+		return TransactionMetadatum{}, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return TransactionMetadatum{}, fmt.Errorf("invalid data after metadatum JSON")
+	}
+
+	return md, nil
+}
+
+// JSON returns the cardano-cli JSON representation of the metadatum.
+func (md *TransactionMetadatum) JSON(schema MetadataJSONSchema) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := md.writeJSON(&buf, schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeMetadatumJSON(dec *json.Decoder, schema MetadataJSONSchema) (TransactionMetadatum, error) {
+	switch schema {
+	case MetadataJSONNoSchema:
+		return decodeNoSchemaMetadatum(dec)
+	case MetadataJSONDetailedSchema:
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return TransactionMetadatum{}, err
+		}
+		return decodeDetailedMetadatum(raw)
+	default:
+		return TransactionMetadatum{}, fmt.Errorf("invalid metadata JSON schema %d", schema)
+	}
+}
+
+func decodeNoSchemaMetadatum(dec *json.Decoder) (TransactionMetadatum, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return TransactionMetadatum{}, err
+	}
+
+	switch v := tok.(type) {
+	case json.Number:
+		n, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok {
+			return TransactionMetadatum{}, fmt.Errorf("invalid metadata integer %v", v)
+		}
+		return NewMetadatumBigInt(n), nil
+	case string:
+		return newNoSchemaMetadatum(v), nil
+	case json.Delim:
+		switch v {
+		case '[':
+			var items []TransactionMetadatum
+			for dec.More() {
+				item, err := decodeNoSchemaMetadatum(dec)
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				items = append(items, item)
+			}
+			if _, err := dec.Token(); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			return NewMetadatumList(items...), nil
+		case '{':
+			var pairs []TransactionMetadatumPair
+			for dec.More() {
+				tok, err := dec.Token()
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				key := tok.(string)
+				value, err := decodeNoSchemaMetadatum(dec)
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				pairs = append(pairs, TransactionMetadatumPair{Key: newNoSchemaMetadatumKey(key), Value: value})
+			}
+			if _, err := dec.Token(); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			return NewMetadatumMap(pairs...), nil
+		}
+	}
+
+	return TransactionMetadatum{}, fmt.Errorf("unsupported metadata JSON value %v", tok)
+}
+
+// newNoSchemaMetadatum returns a bytestring metadatum if s is an hex string
+// prefixed by 0x, otherwise it returns a text metadatum.
+func newNoSchemaMetadatum(s string) TransactionMetadatum {
+	if strings.HasPrefix(s, "0x") {
+		if b, err := hex.DecodeString(s[2:]); err == nil {
+			return NewMetadatumBytes(b)
+		}
+	}
+	return NewMetadatumText(s)
+}
+
+// newNoSchemaMetadatumKey is like newNoSchemaMetadatum but also parses integers,
+// as JSON object keys are always strings.
+func newNoSchemaMetadatumKey(s string) TransactionMetadatum {
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return NewMetadatumBigInt(n)
+	}
+	return newNoSchemaMetadatum(s)
+}
+
+func decodeDetailedMetadatum(data []byte) (TransactionMetadatum, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return TransactionMetadatum{}, err
+	}
+	if len(obj) != 1 {
+		return TransactionMetadatum{}, fmt.Errorf("detailed metadata JSON value must have exactly one key")
+	}
+
+	for key, value := range obj {
+		switch key {
+		case "int":
+			var n json.Number
+			if err := json.Unmarshal(value, &n); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			i, ok := new(big.Int).SetString(n.String(), 10)
+			if !ok {
+				return TransactionMetadatum{}, fmt.Errorf("invalid metadata integer %v", n)
+			}
+			return NewMetadatumBigInt(i), nil
+		case "bytes":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return TransactionMetadatum{}, err
+			}
+			return NewMetadatumBytes(b), nil
+		case "string":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			return NewMetadatumText(s), nil
+		case "list":
+			var rawItems []json.RawMessage
+			if err := json.Unmarshal(value, &rawItems); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			var items []TransactionMetadatum
+			for _, rawItem := range rawItems {
+				item, err := decodeDetailedMetadatum(rawItem)
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				items = append(items, item)
+			}
+			return NewMetadatumList(items...), nil
+		case "map":
+			var rawPairs []struct {
+				K json.RawMessage `json:"k"`
+				V json.RawMessage `json:"v"`
+			}
+			if err := json.Unmarshal(value, &rawPairs); err != nil {
+				return TransactionMetadatum{}, err
+			}
+			var pairs []TransactionMetadatumPair
+			for _, rawPair := range rawPairs {
+				if rawPair.K == nil || rawPair.V == nil {
+					return TransactionMetadatum{}, fmt.Errorf("detailed metadata map entries must have k and v keys")
+				}
+				k, err := decodeDetailedMetadatum(rawPair.K)
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				v, err := decodeDetailedMetadatum(rawPair.V)
+				if err != nil {
+					return TransactionMetadatum{}, err
+				}
+				pairs = append(pairs, TransactionMetadatumPair{Key: k, Value: v})
+			}
+			return NewMetadatumMap(pairs...), nil
+		default:
+			return TransactionMetadatum{}, fmt.Errorf("invalid detailed metadata JSON key %q", key)
+		}
+	}
+
+	return TransactionMetadatum{}, nil
+}
+
+func (md *TransactionMetadatum) writeJSON(buf *bytes.Buffer, schema MetadataJSONSchema) error {
+	switch schema {
+	case MetadataJSONNoSchema:
+		return md.writeNoSchemaJSON(buf)
+	case MetadataJSONDetailedSchema:
+		return md.writeDetailedJSON(buf)
+	default:
+		return fmt.Errorf("invalid metadata JSON schema %d", schema)
+	}
+}
+
+func (md *TransactionMetadatum) writeNoSchemaJSON(buf *bytes.Buffer) error {
+	switch md.Type {
+	case MetadatumInt:
+		buf.WriteString(md.intString())
+	case MetadatumBytes:
+		writeJSONString(buf, "0x"+hex.EncodeToString(md.Bytes))
+	case MetadatumText:
+		writeJSONString(buf, md.Text)
+	case MetadatumList:
+		buf.WriteByte('[')
+		for i, item := range md.List {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := item.writeNoSchemaJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case MetadatumMap:
+		buf.WriteByte('{')
+		for i, pair := range md.Map {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			var key string
+			switch pair.Key.Type {
+			case MetadatumInt:
+				key = pair.Key.intString()
+			case MetadatumBytes:
+				key = "0x" + hex.EncodeToString(pair.Key.Bytes)
+			case MetadatumText:
+				key = pair.Key.Text
+			default:
+				// Lists and maps keys are represented by their JSON encoding
+				keyJSON, err := pair.Key.JSON(MetadataJSONNoSchema)
+				if err != nil {
+					return err
+				}
+				key = string(keyJSON)
+			}
+			writeJSONString(buf, key)
+			buf.WriteByte(':')
+			if err := pair.Value.writeNoSchemaJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("invalid TransactionMetadatum type %d", md.Type)
+	}
+
+	return nil
+}
+
+func (md *TransactionMetadatum) writeDetailedJSON(buf *bytes.Buffer) error {
+	switch md.Type {
+	case MetadatumInt:
+		buf.WriteString(`{"int":`)
+		buf.WriteString(md.intString())
+	case MetadatumBytes:
+		buf.WriteString(`{"bytes":`)
+		writeJSONString(buf, hex.EncodeToString(md.Bytes))
+	case MetadatumText:
+		buf.WriteString(`{"string":`)
+		writeJSONString(buf, md.Text)
+	case MetadatumList:
+		buf.WriteString(`{"list":[`)
+		for i, item := range md.List {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := item.writeDetailedJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case MetadatumMap:
+		buf.WriteString(`{"map":[`)
+		for i, pair := range md.Map {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"k":`)
+			if err := pair.Key.writeDetailedJSON(buf); err != nil {
+				return err
+			}
+			buf.WriteString(`,"v":`)
+			if err := pair.Value.writeDetailedJSON(buf); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+		}
+		buf.WriteByte(']')
+	default:
+		return fmt.Errorf("invalid TransactionMetadatum type %d", md.Type)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func (md *TransactionMetadatum) intString() string {
+	if md.Int == nil {
+		return "0"
+	}
+	return md.Int.String()
+}
+
+// writeJSONString writes s as a JSON string without escaping HTML characters.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Encode appends a newline
+	buf.Truncate(buf.Len() - 1)
+}
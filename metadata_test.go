@@ -0,0 +1,246 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransactionMetadatumEncoding(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(^uint64(0))
+	minNint := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 64))
+
+	testcases := []struct {
+		name     string
+		cborHex  string
+		metadata TransactionMetadatum
+	}{
+		{
+			name:     "Int",
+			cborHex:  "1864",
+			metadata: NewMetadatumInt(100),
+		},
+		{
+			name:     "Negative int",
+			cborHex:  "3863",
+			metadata: NewMetadatumInt(-100),
+		},
+		{
+			name:     "Max int",
+			cborHex:  "1bffffffffffffffff",
+			metadata: NewMetadatumBigInt(maxUint64),
+		},
+		{
+			name:     "Min int",
+			cborHex:  "3bffffffffffffffff",
+			metadata: NewMetadatumBigInt(minNint),
+		},
+		{
+			name:     "Bytes",
+			cborHex:  "43010203",
+			metadata: NewMetadatumBytes([]byte{1, 2, 3}),
+		},
+		{
+			name:     "Text",
+			cborHex:  "63666f6f",
+			metadata: NewMetadatumText("foo"),
+		},
+		{
+			name:     "List",
+			cborHex:  "820163666f6f",
+			metadata: NewMetadatumList(NewMetadatumInt(1), NewMetadatumText("foo")),
+		},
+		{
+			name:    "Map",
+			cborHex: "a263666f6f01410103",
+			metadata: NewMetadatumMap(
+				TransactionMetadatumPair{Key: NewMetadatumText("foo"), Value: NewMetadatumInt(1)},
+				TransactionMetadatumPair{Key: NewMetadatumBytes([]byte{1}), Value: NewMetadatumInt(3)},
+			),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := tc.metadata.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := hex.EncodeToString(encoded), tc.cborHex; got != want {
+				t.Errorf("invalid encoding\ngot: %s\nwant: %s", got, want)
+			}
+
+			var decoded TransactionMetadatum
+			if err := decoded.UnmarshalCBOR(encoded); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, tc.metadata) {
+				t.Errorf("invalid decoding\ngot: %+v\nwant: %+v", decoded, tc.metadata)
+			}
+		})
+	}
+}
+
+func TestTransactionMetadatumLimits(t *testing.T) {
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 64)
+
+	testcases := []struct {
+		name     string
+		metadata TransactionMetadatum
+	}{
+		{
+			name:     "Int too large",
+			metadata: NewMetadatumBigInt(tooLarge),
+		},
+		{
+			name:     "Int too small",
+			metadata: NewMetadatumBigInt(new(big.Int).Sub(new(big.Int).Neg(tooLarge), big.NewInt(1))),
+		},
+		{
+			name:     "Bytes too large",
+			metadata: TransactionMetadatum{Type: MetadatumBytes, Bytes: make([]byte, 65)},
+		},
+		{
+			name:     "Text too large",
+			metadata: TransactionMetadatum{Type: MetadatumText, Text: strings.Repeat("a", 65)},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.metadata.MarshalCBOR(); err == nil {
+				t.Errorf("expected error encoding metadatum")
+			}
+		})
+	}
+}
+
+func TestTransactionMetadatumChunking(t *testing.T) {
+	text := strings.Repeat("a", 63) + "é" + strings.Repeat("b", 70)
+	md := NewMetadatumText(text)
+	if md.Type != MetadatumList || len(md.List) != 3 {
+		t.Fatalf("expected a list of 3 chunks, got %+v", md)
+	}
+	var joined string
+	for _, chunk := range md.List {
+		if len(chunk.Text) > 64 {
+			t.Errorf("chunk larger than 64 bytes: %q", chunk.Text)
+		}
+		joined += chunk.Text
+	}
+	if joined != text {
+		t.Errorf("invalid chunks\ngot: %s\nwant: %s", joined, text)
+	}
+	if got, want := md.List[0].Text, strings.Repeat("a", 63); got != want {
+		t.Errorf("utf-8 character was split\ngot: %s\nwant: %s", got, want)
+	}
+
+	b := bytes.Repeat([]byte{0xff}, 130)
+	md = NewMetadatumBytes(b)
+	if md.Type != MetadatumList || len(md.List) != 3 {
+		t.Fatalf("expected a list of 3 chunks, got %+v", md)
+	}
+	if got, want := len(md.List[2].Bytes), 2; got != want {
+		t.Errorf("invalid last chunk size\ngot: %d\nwant: %d", got, want)
+	}
+}
+
+func TestMetadataJSON(t *testing.T) {
+	metadata := Metadata{
+		1: NewMetadatumMap(
+			TransactionMetadatumPair{Key: NewMetadatumText("name"), Value: NewMetadatumText("<cardano-go>")},
+			TransactionMetadatumPair{Key: NewMetadatumInt(-5), Value: NewMetadatumBytes([]byte{0xca, 0xfe})},
+			TransactionMetadatumPair{Key: NewMetadatumBytes([]byte{0x01}), Value: NewMetadatumList(NewMetadatumInt(1), NewMetadatumInt(2))},
+		),
+		674: NewMetadatumText("hello"),
+	}
+
+	testcases := []struct {
+		name   string
+		schema MetadataJSONSchema
+		json   string
+	}{
+		{
+			name:   "NoSchema",
+			schema: MetadataJSONNoSchema,
+			json:   `{"1":{"name":"<cardano-go>","-5":"0xcafe","0x01":[1,2]},"674":"hello"}`,
+		},
+		{
+			name:   "DetailedSchema",
+			schema: MetadataJSONDetailedSchema,
+			json: `{"1":{"map":[{"k":{"string":"name"},"v":{"string":"<cardano-go>"}},` +
+				`{"k":{"int":-5},"v":{"bytes":"cafe"}},` +
+				`{"k":{"bytes":"01"},"v":{"list":[{"int":1},{"int":2}]}}]},"674":{"string":"hello"}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := metadata.JSON(tc.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.json {
+				t.Errorf("invalid metadata JSON\ngot: %s\nwant: %s", got, tc.json)
+			}
+
+			decoded, err := NewMetadataFromJSON([]byte(tc.json), tc.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, metadata) {
+				t.Errorf("invalid metadata\ngot: %+v\nwant: %+v", decoded, metadata)
+			}
+		})
+	}
+}
+
+func TestMetadataFromJSONChunking(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	metadata, err := NewMetadataFromJSON([]byte(`{"674":{"msg":"`+long+`"}}`), MetadataJSONNoSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := metadata.Metadatum(674)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewMetadatumMap(TransactionMetadatumPair{
+		Key:   NewMetadatumText("msg"),
+		Value: NewMetadatumList(NewMetadatumText(long[:64]), NewMetadatumText(long[64:])),
+	})
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("invalid metadatum\ngot: %+v\nwant: %+v", md, want)
+	}
+
+	auxData := &AuxiliaryData{Metadata: metadata}
+	if _, err := auxData.MarshalCBOR(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetadataUntypedMetadatum(t *testing.T) {
+	metadata := Metadata{
+		0: map[string]interface{}{
+			"hello": "cardano-go",
+		},
+	}
+	md, err := metadata.Metadatum(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewMetadatumMap(TransactionMetadatumPair{
+		Key:   NewMetadatumText("hello"),
+		Value: NewMetadatumText("cardano-go"),
+	})
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("invalid metadatum\ngot: %+v\nwant: %+v", md, want)
+	}
+	if _, err := metadata.Metadatum(1); err == nil {
+		t.Errorf("expected error for missing label")
+	}
+}
@@ -0,0 +1,369 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// NFTMetadataLabel is the metadata label of CIP-25 NFT metadata.
+const NFTMetadataLabel = 721
+
+// NFTMetadataVersion is the CIP-25 metadata version.
+type NFTMetadataVersion uint8
+
+const (
+	// NFTMetadataV1 uses text keys, policy ids are hex-encoded and asset names are UTF-8.
+	NFTMetadataV1 NFTMetadataVersion = 1
+	// NFTMetadataV2 uses the raw bytes of policy ids and asset names as keys.
+	NFTMetadataV2 NFTMetadataVersion = 2
+)
+
+// NFTMetadata is the CIP-25 metadata of a set of NFTs.
+type NFTMetadata struct {
+	Version NFTMetadataVersion
+	Assets  []NFTAsset
+}
+
+// NFTAsset is the CIP-25 metadata of a single NFT.
+type NFTAsset struct {
+	PolicyID    PolicyID
+	AssetName   AssetName
+	Name        string
+	Image       string
+	MediaType   string
+	Description string
+	Files       []NFTFile
+
+	// Properties are the additional properties of the NFT.
+	Properties []TransactionMetadatumPair
+}
+
+// NFTFile is a file of a CIP-25 NFT.
+type NFTFile struct {
+	Name      string
+	MediaType string
+	Src       string
+
+	// Properties are the additional properties of the file.
+	Properties []TransactionMetadatumPair
+}
+
+// NewNFTMetadata returns a new empty NFTMetadata.
+func NewNFTMetadata(version NFTMetadataVersion) *NFTMetadata {
+	return &NFTMetadata{Version: version}
+}
+
+// AddAsset adds the metadata of an NFT.
+func (m *NFTMetadata) AddAsset(asset NFTAsset) *NFTMetadata {
+	m.Assets = append(m.Assets, asset)
+	return m
+}
+
+// Metadatum returns the metadatum of the label 721.
+func (m *NFTMetadata) Metadatum() (TransactionMetadatum, error) {
+	if m.Version != NFTMetadataV1 && m.Version != NFTMetadataV2 {
+		return TransactionMetadatum{}, fmt.Errorf("invalid NFT metadata version %d", m.Version)
+	}
+
+	policies := []TransactionMetadatumPair{}
+	policyIndex := map[string]int{}
+	for _, asset := range m.Assets {
+		policyKey, err := m.policyKey(asset.PolicyID)
+		if err != nil {
+			return TransactionMetadatum{}, err
+		}
+		assetKey, err := m.assetKey(asset.AssetName)
+		if err != nil {
+			return TransactionMetadatum{}, err
+		}
+
+		if asset.Name == "" || asset.Image == "" {
+			return TransactionMetadatum{}, fmt.Errorf("NFT asset %v must have a name and an image", asset.AssetName)
+		}
+
+		policy := asset.PolicyID.String()
+		i, ok := policyIndex[policy]
+		if !ok {
+			i = len(policies)
+			policyIndex[policy] = i
+			policies = append(policies, TransactionMetadatumPair{Key: policyKey, Value: NewMetadatumMap()})
+		}
+		assets := &policies[i].Value
+		assets.Map = append(assets.Map, TransactionMetadatumPair{Key: assetKey, Value: asset.metadatum()})
+	}
+
+	if m.Version == NFTMetadataV2 {
+		policies = append(policies, TransactionMetadatumPair{
+			Key:   NewMetadatumText("version"),
+			Value: NewMetadatumText("2.0"),
+		})
+	}
+
+	return NewMetadatumMap(policies...), nil
+}
+
+// AuxiliaryData returns the auxiliary data containing the NFT metadata.
+func (m *NFTMetadata) AuxiliaryData() (*AuxiliaryData, error) {
+	md, err := m.Metadatum()
+	if err != nil {
+		return nil, err
+	}
+	return &AuxiliaryData{Metadata: Metadata{NFTMetadataLabel: md}}, nil
+}
+
+func (m *NFTMetadata) policyKey(policyID PolicyID) (TransactionMetadatum, error) {
+	if len(policyID.Bytes()) == 0 {
+		return TransactionMetadatum{}, errors.New("missing NFT policy id")
+	}
+	if m.Version == NFTMetadataV2 {
+		return NewMetadatumBytes(policyID.Bytes()), nil
+	}
+	return NewMetadatumText(hex.EncodeToString(policyID.Bytes())), nil
+}
+
+func (m *NFTMetadata) assetKey(name AssetName) (TransactionMetadatum, error) {
+	if m.Version == NFTMetadataV2 {
+		return NewMetadatumBytes(name.Bytes()), nil
+	}
+	if !utf8.Valid(name.Bytes()) {
+		return TransactionMetadatum{}, fmt.Errorf("asset name %x is not valid UTF-8, use NFT metadata version 2", name.Bytes())
+	}
+	return NewMetadatumText(name.String()), nil
+}
+
+func (a *NFTAsset) metadatum() TransactionMetadatum {
+	md := NewMetadatumMap(
+		TransactionMetadatumPair{Key: NewMetadatumText("name"), Value: NewMetadatumText(a.Name)},
+		TransactionMetadatumPair{Key: NewMetadatumText("image"), Value: NewMetadatumText(a.Image)},
+	)
+	if a.MediaType != "" {
+		md.Map = append(md.Map, TransactionMetadatumPair{Key: NewMetadatumText("mediaType"), Value: NewMetadatumText(a.MediaType)})
+	}
+	if a.Description != "" {
+		md.Map = append(md.Map, TransactionMetadatumPair{Key: NewMetadatumText("description"), Value: NewMetadatumText(a.Description)})
+	}
+	if len(a.Files) > 0 {
+		files := NewMetadatumList()
+		for _, file := range a.Files {
+			files.List = append(files.List, file.metadatum())
+		}
+		md.Map = append(md.Map, TransactionMetadatumPair{Key: NewMetadatumText("files"), Value: files})
+	}
+	md.Map = append(md.Map, a.Properties...)
+	return md
+}
+
+func (f *NFTFile) metadatum() TransactionMetadatum {
+	md := NewMetadatumMap(
+		TransactionMetadatumPair{Key: NewMetadatumText("name"), Value: NewMetadatumText(f.Name)},
+		TransactionMetadatumPair{Key: NewMetadatumText("mediaType"), Value: NewMetadatumText(f.MediaType)},
+		TransactionMetadatumPair{Key: NewMetadatumText("src"), Value: NewMetadatumText(f.Src)},
+	)
+	md.Map = append(md.Map, f.Properties...)
+	return md
+}
+
+// NewNFTMetadataFromTx returns the NFT metadata of a transaction.
+func NewNFTMetadataFromTx(tx *Tx) (*NFTMetadata, error) {
+	if tx.AuxiliaryData == nil {
+		return nil, errors.New("transaction has no auxiliary data")
+	}
+	md, err := tx.AuxiliaryData.Metadata.Metadatum(NFTMetadataLabel)
+	if err != nil {
+		return nil, err
+	}
+	return NewNFTMetadataFromMetadatum(md)
+}
+
+// NewNFTMetadataFromMetadatum returns the NFT metadata from the metadatum of the label 721.
+// Both text and bytes keys are accepted for policy ids and asset names.
+func NewNFTMetadataFromMetadatum(md TransactionMetadatum) (*NFTMetadata, error) {
+	if md.Type != MetadatumMap {
+		return nil, errors.New("NFT metadata must be a map")
+	}
+
+	m := NewNFTMetadata(NFTMetadataV1)
+	for _, policy := range md.Map {
+		if policy.Key.Type == MetadatumText && policy.Key.Text == "version" {
+			version, err := nftMetadataVersion(policy.Value)
+			if err != nil {
+				return nil, err
+			}
+			m.Version = version
+			continue
+		}
+
+		policyID, err := nftPolicyID(policy.Key)
+		if err != nil {
+			return nil, err
+		}
+		if policy.Value.Type != MetadatumMap {
+			return nil, fmt.Errorf("NFT metadata of policy %v must be a map", policyID.String())
+		}
+		for _, asset := range policy.Value.Map {
+			assetName, err := nftAssetName(asset.Key)
+			if err != nil {
+				return nil, err
+			}
+			nft, err := newNFTAsset(asset.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid NFT metadata of asset %v: %w", assetName, err)
+			}
+			nft.PolicyID = policyID
+			nft.AssetName = assetName
+			m.Assets = append(m.Assets, nft)
+		}
+	}
+
+	return m, nil
+}
+
+// nftMetadataVersion returns the version of the metadata, it can be either a
+// text ("1.0", "2.0") or an int (1, 2).
+func nftMetadataVersion(md TransactionMetadatum) (NFTMetadataVersion, error) {
+	if md.Type == MetadatumInt && md.Int != nil {
+		switch {
+		case md.Int.IsInt64() && md.Int.Int64() == 1:
+			return NFTMetadataV1, nil
+		case md.Int.IsInt64() && md.Int.Int64() == 2:
+			return NFTMetadataV2, nil
+		default:
+			return 0, fmt.Errorf("invalid NFT metadata version %v", md.Int)
+		}
+	}
+
+	version, err := metadatumString(md)
+	if err != nil {
+		return 0, err
+	}
+	switch version {
+	case "1.0":
+		return NFTMetadataV1, nil
+	case "2.0":
+		return NFTMetadataV2, nil
+	default:
+		return 0, fmt.Errorf("invalid NFT metadata version %q", version)
+	}
+}
+
+func newNFTAsset(md TransactionMetadatum) (NFTAsset, error) {
+	if md.Type != MetadatumMap {
+		return NFTAsset{}, errors.New("asset metadata must be a map")
+	}
+
+	var asset NFTAsset
+	for _, pair := range md.Map {
+		var err error
+		switch metadatumKey(pair.Key) {
+		case "name":
+			asset.Name, err = metadatumString(pair.Value)
+		case "image":
+			asset.Image, err = metadatumString(pair.Value)
+		case "mediaType":
+			asset.MediaType, err = metadatumString(pair.Value)
+		case "description":
+			asset.Description, err = metadatumString(pair.Value)
+		case "files":
+			if pair.Value.Type != MetadatumList {
+				return NFTAsset{}, errors.New("files must be a list")
+			}
+			for _, fileMd := range pair.Value.List {
+				file, err := newNFTFile(fileMd)
+				if err != nil {
+					return NFTAsset{}, err
+				}
+				asset.Files = append(asset.Files, file)
+			}
+		default:
+			asset.Properties = append(asset.Properties, pair)
+		}
+		if err != nil {
+			return NFTAsset{}, err
+		}
+	}
+
+	return asset, nil
+}
+
+func newNFTFile(md TransactionMetadatum) (NFTFile, error) {
+	if md.Type != MetadatumMap {
+		return NFTFile{}, errors.New("file metadata must be a map")
+	}
+
+	var file NFTFile
+	for _, pair := range md.Map {
+		var err error
+		switch metadatumKey(pair.Key) {
+		case "name":
+			file.Name, err = metadatumString(pair.Value)
+		case "mediaType":
+			file.MediaType, err = metadatumString(pair.Value)
+		case "src":
+			file.Src, err = metadatumString(pair.Value)
+		default:
+			file.Properties = append(file.Properties, pair)
+		}
+		if err != nil {
+			return NFTFile{}, err
+		}
+	}
+
+	return file, nil
+}
+
+func nftPolicyID(key TransactionMetadatum) (PolicyID, error) {
+	switch key.Type {
+	case MetadatumText:
+		b, err := hex.DecodeString(key.Text)
+		if err != nil {
+			return PolicyID{}, fmt.Errorf("invalid NFT policy id %q", key.Text)
+		}
+		return NewPolicyIDFromHash(b), nil
+	case MetadatumBytes:
+		return NewPolicyIDFromHash(key.Bytes), nil
+	default:
+		return PolicyID{}, errors.New("NFT policy id must be text or bytes")
+	}
+}
+
+func nftAssetName(key TransactionMetadatum) (AssetName, error) {
+	switch key.Type {
+	case MetadatumText:
+		return NewAssetName(key.Text), nil
+	case MetadatumBytes:
+		return NewAssetNameFromBytes(key.Bytes), nil
+	default:
+		return AssetName{}, errors.New("NFT asset name must be text or bytes")
+	}
+}
+
+// metadatumKey returns the text of a text metadatum, or an empty string otherwise.
+func metadatumKey(md TransactionMetadatum) string {
+	if md.Type != MetadatumText {
+		return ""
+	}
+	return md.Text
+}
+
+// metadatumString returns the string of a text metadatum or of a list of
+// texts, which is how long strings are represented.
+func metadatumString(md TransactionMetadatum) (string, error) {
+	switch md.Type {
+	case MetadatumText:
+		return md.Text, nil
+	case MetadatumList:
+		var sb strings.Builder
+		for _, item := range md.List {
+			if item.Type != MetadatumText {
+				return "", errors.New("expected a list of texts")
+			}
+			sb.WriteString(item.Text)
+		}
+		return sb.String(), nil
+	default:
+		return "", errors.New("expected a text or a list of texts")
+	}
+}
@@ -0,0 +1,191 @@
+package cardano
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/echovl/cardano-go/internal/cbor"
+)
+
+func TestNFTMetadata(t *testing.T) {
+	policyHash, err := NewHash28("1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID := NewPolicyIDFromHash(policyHash)
+	image := "ipfs://" + strings.Repeat("Q", 70)
+
+	testcases := []struct {
+		name    string
+		version NFTMetadataVersion
+		json    string
+	}{
+		{
+			name:    "v1",
+			version: NFTMetadataV1,
+			json: `{"721":{"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361":{"NFT1":{"name":"NFT 1",` +
+				`"image":["ipfs://` + strings.Repeat("Q", 57) + `","` + strings.Repeat("Q", 13) + `"],` +
+				`"mediaType":"image/png","files":[{"name":"file","mediaType":"image/png","src":"ipfs://file"}],` +
+				`"rarity":"rare"},"NFT2":{"name":"NFT 2","image":"ipfs://image"}}}}`,
+		},
+		{
+			name:    "v2",
+			version: NFTMetadataV2,
+			json: `{"721":{"0x1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361":{"0x4e465431":{"name":"NFT 1",` +
+				`"image":["ipfs://` + strings.Repeat("Q", 57) + `","` + strings.Repeat("Q", 13) + `"],` +
+				`"mediaType":"image/png","files":[{"name":"file","mediaType":"image/png","src":"ipfs://file"}],` +
+				`"rarity":"rare"},"0x4e465432":{"name":"NFT 2","image":"ipfs://image"}},"version":"2.0"}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			nftMetadata := NewNFTMetadata(tc.version).
+				AddAsset(NFTAsset{
+					PolicyID:  policyID,
+					AssetName: NewAssetName("NFT1"),
+					Name:      "NFT 1",
+					Image:     image,
+					MediaType: "image/png",
+					Files: []NFTFile{
+						{Name: "file", MediaType: "image/png", Src: "ipfs://file"},
+					},
+					Properties: []TransactionMetadatumPair{
+						{Key: NewMetadatumText("rarity"), Value: NewMetadatumText("rare")},
+					},
+				}).
+				AddAsset(NFTAsset{
+					PolicyID:  policyID,
+					AssetName: NewAssetName("NFT2"),
+					Name:      "NFT 2",
+					Image:     "ipfs://image",
+				})
+
+			auxData, err := nftMetadata.AuxiliaryData()
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotJSON, err := auxData.Metadata.JSON(MetadataJSONNoSchema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(gotJSON) != tc.json {
+				t.Errorf("invalid NFT metadata\ngot: %s\nwant: %s", gotJSON, tc.json)
+			}
+
+			tx := &Tx{IsValid: true, AuxiliaryData: auxData}
+			decodedTx := &Tx{}
+			if err := decodedTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewNFTMetadataFromTx(decodedTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != tc.version {
+				t.Errorf("invalid version\ngot: %v\nwant: %v", got.Version, tc.version)
+			}
+			if len(got.Assets) != len(nftMetadata.Assets) {
+				t.Fatalf("invalid number of assets\ngot: %v\nwant: %v", len(got.Assets), len(nftMetadata.Assets))
+			}
+			// Decoded metadata maps are sorted by key
+			for _, want := range nftMetadata.Assets {
+				var found bool
+				for _, asset := range got.Assets {
+					if asset.AssetName.String() == want.AssetName.String() {
+						found = true
+						if !reflect.DeepEqual(asset, want) {
+							t.Errorf("invalid asset\ngot: %+v\nwant: %+v", asset, want)
+						}
+					}
+				}
+				if !found {
+					t.Errorf("asset %v not found", want.AssetName)
+				}
+			}
+		})
+	}
+}
+
+func TestNFTMetadataInvalidAssetName(t *testing.T) {
+	policyHash, err := NewHash28("1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nftMetadata := NewNFTMetadata(NFTMetadataV1).AddAsset(NFTAsset{
+		PolicyID:  NewPolicyIDFromHash(policyHash),
+		AssetName: AssetName{bs: cbor.NewByteString([]byte{0xff, 0xfe})},
+		Name:      "NFT",
+		Image:     "ipfs://image",
+	})
+	if _, err := nftMetadata.AuxiliaryData(); err == nil {
+		t.Errorf("expected error using a non UTF-8 asset name in v1 metadata")
+	}
+
+	nftMetadata.Version = NFTMetadataV2
+	if _, err := nftMetadata.AuxiliaryData(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNFTMetadataVersion(t *testing.T) {
+	testcases := []struct {
+		name    string
+		version TransactionMetadatum
+		want    NFTMetadataVersion
+		wantErr bool
+	}{
+		{name: "text 1.0", version: NewMetadatumText("1.0"), want: NFTMetadataV1},
+		{name: "text 2.0", version: NewMetadatumText("2.0"), want: NFTMetadataV2},
+		{name: "int 1", version: NewMetadatumInt(1), want: NFTMetadataV1},
+		{name: "int 2", version: NewMetadatumInt(2), want: NFTMetadataV2},
+		{name: "int 3", version: NewMetadatumInt(3), wantErr: true},
+		{name: "text 3.0", version: NewMetadatumText("3.0"), wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			md := NewMetadatumMap(TransactionMetadatumPair{Key: NewMetadatumText("version"), Value: tc.version})
+			got, err := NewNFTMetadataFromMetadatum(md)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected invalid version error")
+			}
+			if got.Version != tc.want {
+				t.Errorf("invalid version\ngot: %v\nwant: %v", got.Version, tc.want)
+			}
+		})
+	}
+}
+
+func TestNFTMetadataMissingFields(t *testing.T) {
+	policyHash, err := NewHash28("1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name  string
+		asset NFTAsset
+	}{
+		{name: "missing name", asset: NFTAsset{Image: "ipfs://image"}},
+		{name: "missing image", asset: NFTAsset{Name: "NFT"}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.asset.PolicyID = NewPolicyIDFromHash(policyHash)
+			tc.asset.AssetName = NewAssetName("NFT")
+			if _, err := NewNFTMetadata(NFTMetadataV1).AddAsset(tc.asset).Metadatum(); err == nil {
+				t.Error("expected missing field error")
+			}
+		})
+	}
+}
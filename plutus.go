@@ -0,0 +1,231 @@
+package cardano
+
+import (
+	"fmt"
+
+	"github.com/echovl/cardano-go/internal/cbor"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Language is a Plutus language version.
+type Language uint8
+
+const (
+	PlutusV1 Language = iota
+	PlutusV2
+	PlutusV3
+)
+
+// String implements Stringer.
+func (l Language) String() string {
+	switch l {
+	case PlutusV1:
+		return "PlutusV1"
+	case PlutusV2:
+		return "PlutusV2"
+	case PlutusV3:
+		return "PlutusV3"
+	default:
+		return fmt.Sprintf("Language(%d)", uint8(l))
+	}
+}
+
+type RedeemerTag uint64
+
+const (
+	RedeemerTagSpend RedeemerTag = iota
+	RedeemerTagMint
+	RedeemerTagCert
+	RedeemerTagReward
+	RedeemerTagVoting
+	RedeemerTagProposing
+)
+
+// ExUnits represents the execution units used by a Plutus script.
+type ExUnits struct {
+	_     struct{} `cbor:",toarray"`
+	Mem   uint64
+	Steps uint64
+}
+
+// ExUnitPrices are the prices of the execution units, in lovelace per unit.
+type ExUnitPrices struct {
+	_     struct{} `cbor:",toarray"`
+	Mem   Rational
+	Steps Rational
+}
+
+// Redeemer is the argument passed to a Plutus script when it's executed.
+// Index refers to the position of the redeemer purpose (input, policy, certificate, etc)
+// once the transaction elements are sorted as the ledger does.
+type Redeemer struct {
+	_       struct{} `cbor:",toarray"`
+	Tag     RedeemerTag
+	Index   uint64
+	Data    PlutusData
+	ExUnits ExUnits
+}
+
+type redeemerKey struct {
+	_     struct{} `cbor:",toarray"`
+	Tag   RedeemerTag
+	Index uint64
+}
+
+type redeemerValue struct {
+	_       struct{} `cbor:",toarray"`
+	Data    PlutusData
+	ExUnits ExUnits
+}
+
+// Redeemers is a list of redeemers.
+// It can be decoded from both the array format and the map format introduced in
+// the Conway era. The witness set re-encodes them in the format they were decoded.
+type Redeemers []Redeemer
+
+// isRedeemersMap returns true if the redeemers are encoded using the map format.
+func isRedeemersMap(data []byte) bool {
+	major, _, _, _, err := decodeCBORHead(data)
+	return err == nil && major == 5
+}
+
+// mapBytes returns the encoding of the redeemers using the map format.
+func (r Redeemers) mapBytes() ([]byte, error) {
+	bytes := encodeCBORHead(5, uint64(len(r)))
+	for _, redeemer := range r {
+		key, err := cborEnc.Marshal(redeemerKey{Tag: redeemer.Tag, Index: redeemer.Index})
+		if err != nil {
+			return nil, err
+		}
+		value, err := cborEnc.Marshal(redeemerValue{Data: redeemer.Data, ExUnits: redeemer.ExUnits})
+		if err != nil {
+			return nil, err
+		}
+		bytes = append(bytes, key...)
+		bytes = append(bytes, value...)
+	}
+	return bytes, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (r *Redeemers) UnmarshalCBOR(data []byte) error {
+	if !isRedeemersMap(data) {
+		rr := []Redeemer{}
+		if err := cborDec.Unmarshal(data, &rr); err != nil {
+			return err
+		}
+		*r = rr
+		return nil
+	}
+
+	pairs, err := getMapPairsFromCBOR(data)
+	if err != nil {
+		return err
+	}
+	rr := make([]Redeemer, len(pairs))
+	for i, pair := range pairs {
+		var (
+			key   redeemerKey
+			value redeemerValue
+		)
+		if err := cborDec.Unmarshal(pair[0], &key); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(pair[1], &value); err != nil {
+			return err
+		}
+		rr[i] = Redeemer{
+			Tag:     key.Tag,
+			Index:   key.Index,
+			Data:    value.Data,
+			ExUnits: value.ExUnits,
+		}
+	}
+	*r = rr
+
+	return nil
+}
+
+// Languages returns the Plutus languages used by the scripts in the witness set.
+func (ws *WitnessSet) Languages() []Language {
+	languages := []Language{}
+	if len(ws.PlutusV1Scripts) > 0 {
+		languages = append(languages, PlutusV1)
+	}
+	if len(ws.PlutusV2Scripts) > 0 {
+		languages = append(languages, PlutusV2)
+	}
+	if len(ws.PlutusV3Scripts) > 0 {
+		languages = append(languages, PlutusV3)
+	}
+	return languages
+}
+
+// LanguageViews returns the CBOR encoding of the cost models used by the given languages,
+// as required for the script data hash.
+// More info could be found in
+// <https://github.com/IntersectMBO/cardano-ledger/blob/master/eras/alonzo/impl/cddl-files/alonzo.cddl>
+func (cm CostModels) LanguageViews(languages []Language) ([]byte, error) {
+	views := map[interface{}]interface{}{}
+	for _, lang := range languages {
+		costModel, ok := cm[lang]
+		if !ok {
+			return nil, fmt.Errorf("missing cost model for %v", lang)
+		}
+		switch lang {
+		case PlutusV1:
+			// PlutusV1 language views are double-bagged and use an indefinite-length
+			// list for the cost model, this is a legacy bug kept by the ledger.
+			key, err := cborEnc.Marshal(lang)
+			if err != nil {
+				return nil, err
+			}
+			value := []byte{0x9f}
+			for _, cost := range costModel {
+				costBytes, err := cborEnc.Marshal(cost)
+				if err != nil {
+					return nil, err
+				}
+				value = append(value, costBytes...)
+			}
+			value = append(value, 0xff)
+			views[cbor.NewByteString(key)] = value
+		default:
+			views[uint64(lang)] = costModel
+		}
+	}
+	return cborEnc.Marshal(views)
+}
+
+// scriptDataHash computes the hash of the redeemers, datums and language views
+// of the witness set. The redeemers are hashed in the format used by the witness set.
+func scriptDataHash(ws *WitnessSet, languageViews []byte, isConway bool) (Hash32, error) {
+	var bytes []byte
+	if len(ws.Redeemers) == 0 {
+		// Only datums are present, the redeemers are empty (an array before Conway
+		// and a map since Conway) and the language views an empty map.
+		if isConway {
+			bytes = append(bytes, 0xa0)
+		} else {
+			bytes = append(bytes, 0x80)
+		}
+		languageViews = []byte{0xa0}
+	} else {
+		redeemersBytes, err := ws.redeemersBytes()
+		if err != nil {
+			return nil, err
+		}
+		bytes = append(bytes, redeemersBytes...)
+	}
+	if len(ws.PlutusData) > 0 {
+		datumsBytes, err := ws.plutusDataBytes()
+		if err != nil {
+			return nil, err
+		}
+		bytes = append(bytes, datumsBytes...)
+	}
+	bytes = append(bytes, languageViews...)
+
+	hash := blake2b.Sum256(bytes)
+	return hash[:], nil
+}
@@ -0,0 +1,330 @@
+package cardano
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/echovl/cardano-go/internal/cbor"
+	"golang.org/x/crypto/blake2b"
+)
+
+// boundedBytesChunkSize is the maximum size of a Plutus bytestring chunk.
+const boundedBytesChunkSize = 64
+
+type PlutusDataType uint8
+
+const (
+	ConstrData PlutusDataType = iota
+	MapData
+	ListData
+	IntegerData
+	BytesData
+)
+
+// PlutusData is the data used by Plutus scripts as datums and redeemers.
+type PlutusData struct {
+	Type PlutusDataType
+
+	// Constr fields
+	Constructor uint64
+	Fields      []PlutusData
+
+	List    []PlutusData
+	Map     []PlutusDataPair
+	Integer *big.Int
+	Bytes   []byte
+
+	// original is the encoding of a decoded PlutusData, it's used to re-encode
+	// it byte-exactly (definite-length arrays and maps, non-minimal integer heads,
+	// chunked bytestrings, etc).
+	original originalCBOR
+}
+
+// PlutusDataPair is a key-value pair of a Plutus Map.
+type PlutusDataPair struct {
+	Key   PlutusData
+	Value PlutusData
+}
+
+// NewConstrData returns a new Constr PlutusData.
+func NewConstrData(constructor uint64, fields ...PlutusData) PlutusData {
+	return PlutusData{Type: ConstrData, Constructor: constructor, Fields: fields}
+}
+
+// NewMapData returns a new Map PlutusData.
+func NewMapData(pairs ...PlutusDataPair) PlutusData {
+	return PlutusData{Type: MapData, Map: pairs}
+}
+
+// NewListData returns a new List PlutusData.
+func NewListData(items ...PlutusData) PlutusData {
+	return PlutusData{Type: ListData, List: items}
+}
+
+// NewIntegerData returns a new Integer PlutusData.
+func NewIntegerData(n *big.Int) PlutusData {
+	return PlutusData{Type: IntegerData, Integer: n}
+}
+
+// NewIntData returns a new Integer PlutusData from an int64.
+func NewIntData(n int64) PlutusData {
+	return NewIntegerData(big.NewInt(n))
+}
+
+// NewBytesData returns a new Bytes PlutusData.
+func NewBytesData(b []byte) PlutusData {
+	return PlutusData{Type: BytesData, Bytes: b}
+}
+
+// Hash returns the datum hash using blake2b256.
+func (pd *PlutusData) Hash() (Hash32, error) {
+	bytes, err := pd.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	hash := blake2b.Sum256(bytes)
+	return hash[:], nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (pd *PlutusData) MarshalCBOR() ([]byte, error) {
+	bytes, err := pd.marshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	return pd.original.bytes(bytes), nil
+}
+
+func (pd *PlutusData) marshalCBOR() ([]byte, error) {
+	switch pd.Type {
+	case ConstrData:
+		fields, err := encodePlutusList(pd.Fields)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case pd.Constructor <= 6:
+			return append(encodeCBORHead(6, 121+pd.Constructor), fields...), nil
+		case pd.Constructor <= 127:
+			return append(encodeCBORHead(6, 1280+pd.Constructor-7), fields...), nil
+		default:
+			constr := append(encodeCBORHead(6, 102), encodeCBORHead(4, 2)...)
+			constr = append(constr, encodeCBORHead(0, pd.Constructor)...)
+			return append(constr, fields...), nil
+		}
+	case MapData:
+		bytes := encodeCBORHead(5, uint64(len(pd.Map)))
+		for _, pair := range pd.Map {
+			key, err := pair.Key.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			value, err := pair.Value.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			bytes = append(bytes, key...)
+			bytes = append(bytes, value...)
+		}
+		return bytes, nil
+	case ListData:
+		return encodePlutusList(pd.List)
+	case IntegerData:
+		return encodePlutusInteger(pd.Integer), nil
+	case BytesData:
+		return encodeBoundedBytes(pd.Bytes), nil
+	default:
+		return nil, fmt.Errorf("cbor: invalid PlutusData type %d", pd.Type)
+	}
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (pd *PlutusData) UnmarshalCBOR(data []byte) error {
+	if err := pd.unmarshalCBOR(data); err != nil {
+		return err
+	}
+	encoded, err := pd.marshalCBOR()
+	if err != nil {
+		return err
+	}
+	pd.original = newOriginalCBOR(data, encoded)
+	return nil
+}
+
+func (pd *PlutusData) unmarshalCBOR(data []byte) error {
+	major, arg, _, _, err := decodeCBORHead(data)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case 0, 1:
+		n := new(big.Int).SetUint64(arg)
+		if major == 1 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		*pd = NewIntegerData(n)
+	case 2:
+		var b []byte
+		if err := cborDec.Unmarshal(data, &b); err != nil {
+			return err
+		}
+		*pd = NewBytesData(b)
+	case 4:
+		items, err := decodePlutusList(data)
+		if err != nil {
+			return err
+		}
+		*pd = NewListData(items...)
+	case 5:
+		pairs, err := getMapPairsFromCBOR(data)
+		if err != nil {
+			return err
+		}
+		m := make([]PlutusDataPair, len(pairs))
+		for i, pair := range pairs {
+			if err := m[i].Key.UnmarshalCBOR(pair[0]); err != nil {
+				return err
+			}
+			if err := m[i].Value.UnmarshalCBOR(pair[1]); err != nil {
+				return err
+			}
+		}
+		*pd = NewMapData(m...)
+	case 6:
+		var tag cbor.RawTag
+		if err := tag.UnmarshalCBOR(data); err != nil {
+			return err
+		}
+		return pd.unmarshalTag(tag)
+	default:
+		return fmt.Errorf("cbor: cannot unmarshal CBOR major type %d into PlutusData", major)
+	}
+
+	return nil
+}
+
+func (pd *PlutusData) unmarshalTag(tag cbor.RawTag) error {
+	var constructor uint64
+	fields := []byte(tag.Content)
+
+	switch {
+	case tag.Number == 2 || tag.Number == 3:
+		var b []byte
+		if err := cborDec.Unmarshal(tag.Content, &b); err != nil {
+			return err
+		}
+		n := new(big.Int).SetBytes(b)
+		if tag.Number == 3 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		*pd = NewIntegerData(n)
+		return nil
+	case tag.Number >= 121 && tag.Number <= 127:
+		constructor = tag.Number - 121
+	case tag.Number >= 1280 && tag.Number <= 1400:
+		constructor = tag.Number - 1280 + 7
+	case tag.Number == 102:
+		var constr []cbor.RawMessage
+		if err := cborDec.Unmarshal(tag.Content, &constr); err != nil {
+			return err
+		}
+		if len(constr) != 2 {
+			return fmt.Errorf("cbor: invalid PlutusData constructor, expected 2 elements got %d", len(constr))
+		}
+		if err := cborDec.Unmarshal(constr[0], &constructor); err != nil {
+			return err
+		}
+		fields = constr[1]
+	default:
+		return fmt.Errorf("cbor: invalid PlutusData tag %d", tag.Number)
+	}
+
+	items, err := decodePlutusList(fields)
+	if err != nil {
+		return err
+	}
+	*pd = NewConstrData(constructor, items...)
+
+	return nil
+}
+
+// encodePlutusList encodes a list of PlutusData. Non-empty lists are encoded
+// using indefinite-length arrays, as the ledger does.
+func encodePlutusList(items []PlutusData) ([]byte, error) {
+	var bytes []byte
+	if len(items) == 0 {
+		bytes = encodeCBORHead(4, uint64(len(items)))
+	} else {
+		bytes = []byte{0x9f}
+	}
+	for _, item := range items {
+		itemBytes, err := item.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		bytes = append(bytes, itemBytes...)
+	}
+	if len(items) != 0 {
+		bytes = append(bytes, 0xff)
+	}
+	return bytes, nil
+}
+
+func decodePlutusList(data []byte) ([]PlutusData, error) {
+	major, _, _, _, err := decodeCBORHead(data)
+	if err != nil {
+		return nil, err
+	}
+	if major != 4 {
+		return nil, fmt.Errorf("cbor: expected CBOR array got major type %d", major)
+	}
+	var items []PlutusData
+	if err := cborDec.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		items = nil
+	}
+	return items, nil
+}
+
+// encodePlutusInteger encodes an integer as a CBOR integer if it fits in 64 bits,
+// otherwise it's encoded as a bignum.
+func encodePlutusInteger(n *big.Int) []byte {
+	if n == nil {
+		n = new(big.Int)
+	}
+	if n.Sign() >= 0 {
+		if n.IsUint64() {
+			return encodeCBORHead(0, n.Uint64())
+		}
+		return append(encodeCBORHead(6, 2), encodeBoundedBytes(n.Bytes())...)
+	}
+	// Negative integers are encoded as -1 - n
+	abs := new(big.Int).Neg(n)
+	abs.Sub(abs, big.NewInt(1))
+	if abs.IsUint64() {
+		return encodeCBORHead(1, abs.Uint64())
+	}
+	return append(encodeCBORHead(6, 3), encodeBoundedBytes(abs.Bytes())...)
+}
+
+// encodeBoundedBytes encodes a bytestring, bytestrings larger than 64 bytes are
+// encoded as indefinite-length bytestrings with 64 bytes chunks.
+func encodeBoundedBytes(b []byte) []byte {
+	if len(b) <= boundedBytesChunkSize {
+		return append(encodeCBORHead(2, uint64(len(b))), b...)
+	}
+	bytes := []byte{0x5f}
+	for len(b) > 0 {
+		size := boundedBytesChunkSize
+		if len(b) < size {
+			size = len(b)
+		}
+		bytes = append(bytes, encodeCBORHead(2, uint64(size))...)
+		bytes = append(bytes, b[:size]...)
+		b = b[size:]
+	}
+	return append(bytes, 0xff)
+}
@@ -0,0 +1,198 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestPlutusDataEncoding(t *testing.T) {
+	twoTo64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	minusTwoTo64 := new(big.Int).Neg(twoTo64)
+
+	testcases := []struct {
+		name    string
+		cborHex string
+		data    PlutusData
+	}{
+		{
+			name:    "Unit",
+			cborHex: "d87980",
+			data:    NewConstrData(0),
+		},
+		{
+			name:    "Constr 1 with fields",
+			cborHex: "d87a9f0140ff",
+			data:    NewConstrData(1, NewIntData(1), NewBytesData([]byte{})),
+		},
+		{
+			name:    "Constr 7",
+			cborHex: "d9050080",
+			data:    NewConstrData(7),
+		},
+		{
+			name:    "Constr 127",
+			cborHex: "d9057880",
+			data:    NewConstrData(127),
+		},
+		{
+			name:    "Constr 128",
+			cborHex: "d86682188080",
+			data:    NewConstrData(128),
+		},
+		{
+			name:    "Map",
+			cborHex: "a2024102014101",
+			data: NewMapData(
+				PlutusDataPair{Key: NewIntData(2), Value: NewBytesData([]byte{0x02})},
+				PlutusDataPair{Key: NewIntData(1), Value: NewBytesData([]byte{0x01})},
+			),
+		},
+		{
+			name:    "Map with constr keys",
+			cborHex: "a1d8798001",
+			data: NewMapData(
+				PlutusDataPair{Key: NewConstrData(0), Value: NewIntData(1)},
+			),
+		},
+		{
+			name:    "List",
+			cborHex: "9f0120ff",
+			data:    NewListData(NewIntData(1), NewIntData(-1)),
+		},
+		{
+			name:    "Empty list",
+			cborHex: "80",
+			data:    NewListData(),
+		},
+		{
+			name:    "Integer",
+			cborHex: "1b7fffffffffffffff",
+			data:    NewIntData(9223372036854775807),
+		},
+		{
+			name:    "Negative integer",
+			cborHex: "3bffffffffffffffff",
+			data:    NewIntegerData(minusTwoTo64),
+		},
+		{
+			name:    "Bignum",
+			cborHex: "c249010000000000000000",
+			data:    NewIntegerData(twoTo64),
+		},
+		{
+			name:    "Negative bignum",
+			cborHex: "c349010000000000000000",
+			data:    NewIntegerData(new(big.Int).Sub(minusTwoTo64, big.NewInt(1))),
+		},
+		{
+			name:    "Bytes",
+			cborHex: "4401020304",
+			data:    NewBytesData([]byte{1, 2, 3, 4}),
+		},
+		{
+			name:    "Chunked bytes",
+			cborHex: "5f5840000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004100ff",
+			data:    NewBytesData(make([]byte, 65)),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := hex.DecodeString(tc.cborHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tc.data.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("invalid encoding\ngot: %x\nwant: %x", got, want)
+			}
+
+			var pd PlutusData
+			if err := pd.UnmarshalCBOR(want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pd, tc.data) {
+				t.Errorf("invalid decoding\ngot: %+v\nwant: %+v", pd, tc.data)
+			}
+		})
+	}
+}
+
+func TestPlutusDataRoundTrip(t *testing.T) {
+	testcases := []string{
+		// Definite-length constr fields
+		"d8798201d87a80",
+		// Definite-length list inside indefinite-length constr fields
+		"d8799f820102ff",
+		// Nested structures
+		"d8799fa1d87a9f01ff9f4102ff80ff",
+		// Indefinite-length map
+		"bf0102ff",
+		// Empty indefinite-length list
+		"9fff",
+		// Tag 102 constr with an empty indefinite-length list
+		"d8668218809fff",
+		// Chunked bytestring
+		"5f4101ff",
+		// Non-minimal integer head
+		"1801",
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc, func(t *testing.T) {
+			data, err := hex.DecodeString(tc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pd PlutusData
+			if err := cborDec.Unmarshal(data, &pd); err != nil {
+				t.Fatal(err)
+			}
+			got, err := cborEnc.Marshal(&pd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("invalid encoding\ngot: %x\nwant: %x", got, data)
+			}
+		})
+	}
+}
+
+func TestPlutusDataHash(t *testing.T) {
+	testcases := []struct {
+		name string
+		data PlutusData
+		hash string
+	}{
+		{
+			name: "Integer 42",
+			data: NewIntData(42),
+			hash: "9e1199a988ba72ffd6e9c269cadb3b53b5f360ff99f112d9b2ee30c4d74ad88b",
+		},
+		{
+			name: "Unit",
+			data: NewConstrData(0),
+			hash: "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := tc.data.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := hash.String(), tc.hash; got != want {
+				t.Errorf("invalid datum hash\ngot: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestLanguageViews(t *testing.T) {
+	costModels := CostModels{
+		PlutusV1: {1, 2},
+		PlutusV2: {3},
+		PlutusV3: {4, 500},
+	}
+
+	testcases := []struct {
+		name      string
+		languages []Language
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "PlutusV1",
+			languages: []Language{PlutusV1},
+			want:      "a14100449f0102ff",
+		},
+		{
+			name:      "PlutusV1 and PlutusV2",
+			languages: []Language{PlutusV1, PlutusV2},
+			want:      "a2018103410044" + "9f0102ff",
+		},
+		{
+			name:      "PlutusV2 and PlutusV3",
+			languages: []Language{PlutusV2, PlutusV3},
+			want:      "a20181030282041901f4",
+		},
+		{
+			name:      "no languages",
+			languages: []Language{},
+			want:      "a0",
+		},
+		{
+			name:      "missing cost model",
+			languages: []Language{Language(3)},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			views, err := costModels.LanguageViews(tc.languages)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(views); got != tc.want {
+				t.Errorf("invalid language views\ngot: %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScriptDataHash(t *testing.T) {
+	redeemers := []Redeemer{
+		{Tag: RedeemerTagSpend, Index: 0, Data: NewIntData(42), ExUnits: ExUnits{Mem: 100, Steps: 200}},
+	}
+	datums := []PlutusData{NewIntData(1)}
+
+	testcases := []struct {
+		name      string
+		redeemers []Redeemer
+		datums    []PlutusData
+		conway    bool
+		views     string
+		preimage  string
+	}{
+		{
+			name:      "redeemers and datums",
+			redeemers: redeemers,
+			datums:    datums,
+			views:     "a14100449f0102ff",
+			preimage:  "81840000182a82186418c8" + "8101" + "a14100449f0102ff",
+		},
+		{
+			name:      "only redeemers",
+			redeemers: redeemers,
+			views:     "a14100449f0102ff",
+			preimage:  "81840000182a82186418c8" + "a14100449f0102ff",
+		},
+		{
+			name:     "only datums",
+			datums:   datums,
+			views:    "a14100449f0102ff",
+			preimage: "80" + "8101" + "a0",
+		},
+		{
+			name:     "only datums conway",
+			datums:   datums,
+			conway:   true,
+			views:    "a14100449f0102ff",
+			preimage: "a0" + "8101" + "a0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			views, err := hex.DecodeString(tc.views)
+			if err != nil {
+				t.Fatal(err)
+			}
+			preimage, err := hex.DecodeString(tc.preimage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ws := &WitnessSet{Redeemers: tc.redeemers, PlutusData: tc.datums}
+			got, err := scriptDataHash(ws, views, tc.conway)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := blake2b.Sum256(preimage)
+			if got.String() != hex.EncodeToString(want[:]) {
+				t.Errorf("invalid script data hash\ngot: %s\nwant: %x", got, want)
+			}
+		})
+	}
+}
+
+func TestRedeemersEncoding(t *testing.T) {
+	want := Redeemers{
+		{Tag: RedeemerTagSpend, Index: 0, Data: NewIntData(42), ExUnits: ExUnits{Mem: 100, Steps: 200}},
+		{Tag: RedeemerTagMint, Index: 1, Data: NewBytesData([]byte{0xca, 0xfe}), ExUnits: ExUnits{Mem: 1, Steps: 2}},
+	}
+
+	testcases := []struct {
+		name    string
+		cborHex string
+	}{
+		{
+			name:    "array",
+			cborHex: "82" + "840000182a82186418c8" + "84010142cafe820102",
+		},
+		{
+			name:    "map",
+			cborHex: "a2" + "82000082182a82186418c8" + "82010182" + "42cafe820102",
+		},
+		{
+			name:    "indefinite map",
+			cborHex: "bf" + "82000082182a82186418c8" + "82010182" + "42cafe820102" + "ff",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.cborHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got Redeemers
+			if err := cborDec.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got: %+v\nwant: %+v", got, want)
+			}
+
+			// Modified redeemers are re-encoded in the format they were decoded
+			ws := &WitnessSet{}
+			if err := ws.UnmarshalCBOR(append([]byte{0xa1, 0x05}, data...)); err != nil {
+				t.Fatal(err)
+			}
+			ws.Redeemers[0].ExUnits = ExUnits{Mem: 1, Steps: 2}
+			wsBytes, err := ws.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := wsBytes[2]>>5 == 5, tc.name != "array"; got != want {
+				t.Errorf("invalid redeemers format\ngot map: %v\nwant map: %v", got, want)
+			}
+			decoded := &WitnessSet{}
+			if err := decoded.UnmarshalCBOR(wsBytes); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded.Redeemers, ws.Redeemers) {
+				t.Errorf("got: %+v\nwant: %+v", decoded.Redeemers, ws.Redeemers)
+			}
+		})
+	}
+}
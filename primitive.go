@@ -236,6 +236,11 @@ func NewAssetName(name string) AssetName {
 	return AssetName{bs: cbor.NewByteString([]byte(name))}
 }
 
+// NewAssetNameFromBytes returns a new AssetName from raw bytes.
+func NewAssetNameFromBytes(name []byte) AssetName {
+	return AssetName{bs: cbor.NewByteString(name)}
+}
+
 // Bytes returns the underlying name bytes.
 func (an *AssetName) Bytes() []byte {
 	return an.bs.Bytes()
@@ -328,6 +333,26 @@ func (ma MultiAsset) String() string {
 	return fmt.Sprintf("%+v", vMap)
 }
 
+func (ma *MultiAsset) add(policyID, name cbor.ByteString, val BigNum) {
+	assets, ok := ma.m[policyID]
+	if !ok {
+		assets = NewAssets()
+		ma.m[policyID] = assets
+	}
+	assets.m[name] += val
+}
+
+func (ma *MultiAsset) remove(policyID, name cbor.ByteString) {
+	assets, ok := ma.m[policyID]
+	if !ok {
+		return
+	}
+	delete(assets.m, name)
+	if len(assets.m) == 0 {
+		delete(ma.m, policyID)
+	}
+}
+
 func (ma *MultiAsset) numPIDs() uint {
 	return uint(len(ma.m))
 }
@@ -540,6 +565,27 @@ type Rational struct {
 	Q uint64
 }
 
+// NewRationalFromString returns the exact Rational of a decimal ("0.0577") or
+// fraction ("577/10000") string. An empty string is zero.
+func NewRationalFromString(s string) (Rational, error) {
+	if s == "" {
+		return Rational{}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() < 0 || !r.Num().IsUint64() || !r.Denom().IsUint64() {
+		return Rational{}, fmt.Errorf("invalid rational number %q", s)
+	}
+	return Rational{P: r.Num().Uint64(), Q: r.Denom().Uint64()}, nil
+}
+
+// rat returns the rational as a big.Rat, a zero denominator is treated as zero.
+func (r Rational) rat() *big.Rat {
+	if r.Q == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(new(big.Int).SetUint64(r.P), new(big.Int).SetUint64(r.Q))
+}
+
 // MarshalCBOR implements cbor.Marshaler
 func (r *Rational) MarshalCBOR() ([]byte, error) {
 	type rational Rational
@@ -595,3 +641,56 @@ func (r *Rational) tagSet(contentType interface{}) (cbor.TagSet, error) {
 
 	return tags, nil
 }
+
+// Withdrawals is a set of reward withdrawals indexed by reward address.
+type Withdrawals struct {
+	m map[cbor.ByteString]Coin
+}
+
+// NewWithdrawals returns a new empty Withdrawals.
+func NewWithdrawals() *Withdrawals {
+	return &Withdrawals{m: make(map[cbor.ByteString]Coin)}
+}
+
+// Set sets the amount withdrawn from a given reward address.
+func (w *Withdrawals) Set(addr Address, amount Coin) *Withdrawals {
+	w.m[cbor.NewByteString(addr.Bytes())] = amount
+	return w
+}
+
+// Get returns the amount withdrawn from a given reward address.
+func (w *Withdrawals) Get(addr Address) Coin {
+	return w.m[cbor.NewByteString(addr.Bytes())]
+}
+
+// Keys returns all the reward addresses stored in Withdrawals.
+func (w *Withdrawals) Keys() ([]Address, error) {
+	addrs := []Address{}
+	for k := range w.m {
+		addr, err := NewAddressFromBytes(k.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// Total returns the total amount withdrawn.
+func (w *Withdrawals) Total() Coin {
+	var total Coin
+	for _, amount := range w.m {
+		total += amount
+	}
+	return total
+}
+
+// MarshalCBOR implements cbor.Marshaler
+func (w *Withdrawals) MarshalCBOR() ([]byte, error) {
+	return cborEnc.Marshal(w.m)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (w *Withdrawals) UnmarshalCBOR(data []byte) error {
+	return cborDec.Unmarshal(data, &w.m)
+}
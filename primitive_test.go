@@ -297,3 +297,72 @@ func TestValueArithmetic(t *testing.T) {
 		})
 	}
 }
+
+func TestWithdrawalsEncoding(t *testing.T) {
+	stakeKey := crypto.NewXPrvKeyFromEntropy([]byte("stake"), "")
+	stakeCred, err := NewKeyCredential(stakeKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := Address{Network: Mainnet, Type: Reward, Stake: stakeCred}
+
+	wantWithdrawals := NewWithdrawals().Set(rewardAddr, 1e6)
+	gotWithdrawals := NewWithdrawals()
+	bytes, err := wantWithdrawals.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gotWithdrawals.UnmarshalCBOR(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(wantWithdrawals, gotWithdrawals) {
+		t.Errorf("invalid Withdrawals encoding:\ngot: %v\nwant: %v\n", gotWithdrawals, wantWithdrawals)
+	}
+
+	addrs, err := gotWithdrawals.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || !reflect.DeepEqual(addrs[0], rewardAddr) {
+		t.Errorf("invalid Withdrawals keys:\ngot: %v\nwant: %v\n", addrs, []Address{rewardAddr})
+	}
+	if got, want := gotWithdrawals.Get(rewardAddr), Coin(1e6); got != want {
+		t.Errorf("invalid withdrawal amount: got %v want %v", got, want)
+	}
+}
+
+func TestNewRationalFromString(t *testing.T) {
+	testcases := []struct {
+		s       string
+		want    Rational
+		wantErr bool
+	}{
+		{s: "", want: Rational{}},
+		{s: "15", want: Rational{P: 15, Q: 1}},
+		{s: "0.0577", want: Rational{P: 577, Q: 10000}},
+		{s: "7.21e-5", want: Rational{P: 721, Q: 10000000}},
+		{s: "1/3", want: Rational{P: 1, Q: 3}},
+		{s: "-1", wantErr: true},
+		{s: "abc", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.s, func(t *testing.T) {
+			got, err := NewRationalFromString(tc.s)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected invalid rational error")
+			}
+			if got != tc.want {
+				t.Errorf("invalid rational\ngot: %v\nwant: %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package cardano
 
+import (
+	"fmt"
+
+	"github.com/echovl/cardano-go/internal/cbor"
+)
+
 // ProtocolParams is a Cardano Protocol Parameters.
 type ProtocolParams struct {
 	MinFeeA              Coin
@@ -18,14 +24,37 @@ type ProtocolParams struct {
 	ExtraEntropy         []byte
 	ProtocolVersion      ProtocolVersion
 	MinPoolCost          Coin
-	CoinsPerUTXOWord     Coin
-	CostModels           interface{}
-	ExecutionCosts       interface{}
-	MaxTxExUnits         interface{}
-	MaxBlockTxExUnits    interface{}
+	CoinsPerUTXOWord     Coin // Alonzo
+	CoinsPerUTxOByte     Coin // Babbage and later
+	CostModels           CostModels
+	ExecutionCosts       ExUnitPrices
+	MaxTxExUnits         ExUnits
+	MaxBlockTxExUnits    ExUnits
 	MaxValueSize         uint
 	CollateralPercentage uint
 	MaxCollateralInputs  uint
+
+	MinFeeRefScriptCostPerByte Rational // Conway
+}
+
+// babbageMajorVersion is the first protocol major version of the Babbage era.
+const babbageMajorVersion = 7
+
+// IsBabbage returns true if the parameters are from the Babbage era or later. When
+// the protocol version is not set, CoinsPerUTxOByte is used to detect the era.
+func (p *ProtocolParams) IsBabbage() bool {
+	if p.ProtocolVersion.Major == 0 {
+		return p.CoinsPerUTxOByte > 0
+	}
+	return p.ProtocolVersion.Major >= babbageMajorVersion
+}
+
+// conwayMajorVersion is the first protocol major version of the Conway era.
+const conwayMajorVersion = 9
+
+// IsConway returns true if the parameters are from the Conway era or later.
+func (p *ProtocolParams) IsConway() bool {
+	return p.ProtocolVersion.Major >= conwayMajorVersion
 }
 
 // ProtocolVersion is the protocol version number.
@@ -34,3 +63,131 @@ type ProtocolVersion struct {
 	Major uint
 	Minor uint
 }
+
+// CostModels are the cost models used by the Plutus languages.
+type CostModels map[Language][]int64
+
+// Nonce is the extra entropy nonce. A nil Hash is the neutral nonce.
+type Nonce struct {
+	Hash Hash32
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (n *Nonce) MarshalCBOR() ([]byte, error) {
+	if n.Hash == nil {
+		return cborEnc.Marshal([]interface{}{0})
+	}
+	return cborEnc.Marshal([]interface{}{1, n.Hash})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (n *Nonce) UnmarshalCBOR(data []byte) error {
+	var nonce []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &nonce); err != nil {
+		return err
+	}
+	if len(nonce) == 2 {
+		return cborDec.Unmarshal(nonce[1], &n.Hash)
+	}
+	n.Hash = nil
+	return nil
+}
+
+// ProtocolParamUpdate is a set of protocol parameters changes, unchanged
+// parameters are nil. It contains the parameters of every era, each era only
+// uses a subset of them.
+type ProtocolParamUpdate struct {
+	MinFeeA                    *Coin            `cbor:"0,keyasint,omitempty"`
+	MinFeeB                    *Coin            `cbor:"1,keyasint,omitempty"`
+	MaxBlockBodySize           *uint64          `cbor:"2,keyasint,omitempty"`
+	MaxTxSize                  *uint64          `cbor:"3,keyasint,omitempty"`
+	MaxBlockHeaderSize         *uint64          `cbor:"4,keyasint,omitempty"`
+	KeyDeposit                 *Coin            `cbor:"5,keyasint,omitempty"`
+	PoolDeposit                *Coin            `cbor:"6,keyasint,omitempty"`
+	MaxEpoch                   *uint64          `cbor:"7,keyasint,omitempty"`
+	NOpt                       *uint64          `cbor:"8,keyasint,omitempty"`
+	PoolPledgeInfluence        *Rational        `cbor:"9,keyasint,omitempty"`
+	ExpansionRate              *UnitInterval    `cbor:"10,keyasint,omitempty"`
+	TreasuryGrowthRate         *UnitInterval    `cbor:"11,keyasint,omitempty"`
+	D                          *UnitInterval    `cbor:"12,keyasint,omitempty"` // Shelley to Alonzo
+	ExtraEntropy               *Nonce           `cbor:"13,keyasint,omitempty"` // Shelley to Alonzo
+	ProtocolVersion            *ProtocolVersion `cbor:"14,keyasint,omitempty"` // Shelley to Babbage
+	MinUTxOValue               *Coin            `cbor:"15,keyasint,omitempty"` // Shelley to Mary
+	MinPoolCost                *Coin            `cbor:"16,keyasint,omitempty"`
+	CoinsPerUTxO               *Coin            `cbor:"17,keyasint,omitempty"` // per word in Alonzo, per byte since Babbage
+	CostModels                 CostModels       `cbor:"18,keyasint,omitempty"`
+	ExecutionCosts             *ExUnitPrices    `cbor:"19,keyasint,omitempty"`
+	MaxTxExUnits               *ExUnits         `cbor:"20,keyasint,omitempty"`
+	MaxBlockExUnits            *ExUnits         `cbor:"21,keyasint,omitempty"`
+	MaxValueSize               *uint64          `cbor:"22,keyasint,omitempty"`
+	CollateralPercentage       *uint64          `cbor:"23,keyasint,omitempty"`
+	MaxCollateralInputs        *uint64          `cbor:"24,keyasint,omitempty"`
+	PoolVotingThresholds       []UnitInterval   `cbor:"25,keyasint,omitempty"` // Conway
+	DRepVotingThresholds       []UnitInterval   `cbor:"26,keyasint,omitempty"` // Conway
+	MinCommitteeSize           *uint64          `cbor:"27,keyasint,omitempty"` // Conway
+	CommitteeTermLimit         *uint64          `cbor:"28,keyasint,omitempty"` // Conway
+	GovActionValidityPeriod    *uint64          `cbor:"29,keyasint,omitempty"` // Conway
+	GovActionDeposit           *Coin            `cbor:"30,keyasint,omitempty"` // Conway
+	DRepDeposit                *Coin            `cbor:"31,keyasint,omitempty"` // Conway
+	DRepInactivityPeriod       *uint64          `cbor:"32,keyasint,omitempty"` // Conway
+	MinFeeRefScriptCostPerByte *Rational        `cbor:"33,keyasint,omitempty"` // Conway
+}
+
+// ProposedUpdate is a protocol parameters update proposed by a genesis delegate.
+type ProposedUpdate struct {
+	GenesisHash Hash28
+	Params      ProtocolParamUpdate
+}
+
+// Update is a protocol parameters update proposal for an epoch.
+// It's used from Shelley to Babbage, Conway uses governance actions instead.
+type Update struct {
+	ProposedUpdates []ProposedUpdate
+	Epoch           uint64
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (u *Update) MarshalCBOR() ([]byte, error) {
+	proposals := encodeCBORHead(5, uint64(len(u.ProposedUpdates)))
+	for _, proposal := range u.ProposedUpdates {
+		hash, err := cborEnc.Marshal(proposal.GenesisHash)
+		if err != nil {
+			return nil, err
+		}
+		params, err := cborEnc.Marshal(proposal.Params)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, hash...)
+		proposals = append(proposals, params...)
+	}
+	return cborEnc.Marshal([]interface{}{cbor.RawMessage(proposals), u.Epoch})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (u *Update) UnmarshalCBOR(data []byte) error {
+	var update []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &update); err != nil {
+		return err
+	}
+	if len(update) != 2 {
+		return fmt.Errorf("cbor: invalid Update, expected 2 elements got %d", len(update))
+	}
+
+	pairs, err := getMapPairsFromCBOR(update[0])
+	if err != nil {
+		return err
+	}
+	proposals := make([]ProposedUpdate, len(pairs))
+	for i, pair := range pairs {
+		if err := cborDec.Unmarshal(pair[0], &proposals[i].GenesisHash); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(pair[1], &proposals[i].Params); err != nil {
+			return err
+		}
+	}
+	u.ProposedUpdates = proposals
+
+	return cborDec.Unmarshal(update[1], &u.Epoch)
+}
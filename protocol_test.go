@@ -0,0 +1,85 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpdateEncoding(t *testing.T) {
+	genesisA := strings.Repeat("00", 28)
+	genesisB := "01" + strings.Repeat("00", 27)
+	nonce := strings.Repeat("11", 32)
+
+	updateHex := "82a2" +
+		"581c" + genesisA +
+		"a5" + "00182c" + "0ad81e820102" + "0d82015820" + nonce + "0e820700" + "111910d6" +
+		"581c" + genesisB +
+		"a2" + "12a100820102" + "1382d81e82190241192710d81e821902d11a00989680" +
+		"19015e"
+
+	data, err := hex.DecodeString(updateHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := Update{}
+	if err := update.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+
+	minFeeA, coinsPerUTxO := Coin(44), Coin(4310)
+	nonceHash, _ := NewHash32(nonce)
+	genesisAHash, _ := hex.DecodeString(genesisA)
+	genesisBHash, _ := hex.DecodeString(genesisB)
+	want := Update{
+		ProposedUpdates: []ProposedUpdate{
+			{
+				GenesisHash: genesisAHash,
+				Params: ProtocolParamUpdate{
+					MinFeeA:         &minFeeA,
+					ExpansionRate:   &UnitInterval{P: 1, Q: 2},
+					ExtraEntropy:    &Nonce{Hash: nonceHash},
+					ProtocolVersion: &ProtocolVersion{Major: 7},
+					CoinsPerUTxO:    &coinsPerUTxO,
+				},
+			},
+			{
+				GenesisHash: genesisBHash,
+				Params: ProtocolParamUpdate{
+					CostModels: CostModels{PlutusV1: {1, 2}},
+					ExecutionCosts: &ExUnitPrices{
+						Mem:   Rational{P: 577, Q: 10000},
+						Steps: Rational{P: 721, Q: 10000000},
+					},
+				},
+			},
+		},
+		Epoch: 350,
+	}
+
+	if !reflect.DeepEqual(update, want) {
+		t.Errorf("invalid update decoding\ngot: %+v\nwant: %+v", update, want)
+	}
+
+	got, err := update.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(got) != updateHex {
+		t.Errorf("invalid update encoding\ngot: %x\nwant: %s", got, updateHex)
+	}
+}
+
+func TestProtocolParamUpdateUnknownKeys(t *testing.T) {
+	// Parameter updates from future eras must not fail to decode
+	data, _ := hex.DecodeString("a200182c186301")
+	params := ProtocolParamUpdate{}
+	if err := cborDec.Unmarshal(data, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.MinFeeA == nil || *params.MinFeeA != 44 {
+		t.Errorf("invalid min fee a\ngot: %v\nwant: %v", params.MinFeeA, 44)
+	}
+}
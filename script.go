@@ -1,18 +1,31 @@
 package cardano
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/cbor"
 )
 
 type ScriptHashNamespace uint8
 
 const (
 	NativeScriptNamespace ScriptHashNamespace = iota
-	PlutusScriptNamespace
+	PlutusV1ScriptNamespace
+	PlutusV2ScriptNamespace
+	PlutusV3ScriptNamespace
 )
 
+// PlutusScriptNamespace is the namespace used by PlutusV1 scripts.
+//
+// Deprecated: use PlutusV1ScriptNamespace instead.
+const PlutusScriptNamespace = PlutusV1ScriptNamespace
+
 type NativeScriptType uint64
 
 const (
@@ -85,8 +98,7 @@ func (ns *NativeScript) Hash() (Hash28, error) {
 	if err != nil {
 		return nil, err
 	}
-	bytes = append([]byte{byte(NativeScriptNamespace)}, bytes...)
-	return Blake224Hash(append(bytes))
+	return hashScript(NativeScriptNamespace, bytes)
 }
 
 // Bytes returns the CBOR encoding of the script as bytes.
@@ -165,3 +177,521 @@ func (ns *NativeScript) UnmarshalCBOR(data []byte) error {
 
 	return nil
 }
+
+// nativeScriptJSON is the cardano-cli JSON representation of a native script.
+type nativeScriptJSON struct {
+	Type     string          `json:"type"`
+	KeyHash  string          `json:"keyHash,omitempty"`
+	Required *uint64         `json:"required,omitempty"`
+	Slot     *uint64         `json:"slot,omitempty"`
+	Scripts  *[]NativeScript `json:"scripts,omitempty"`
+}
+
+// cslNativeScriptJSON is the cardano-serialization-lib JSON representation of a
+// native script, the script type is the key of the object containing it.
+type cslNativeScriptJSON struct {
+	AddrKeyHash   *string         `json:"addr_keyhash"`
+	NativeScripts *[]NativeScript `json:"native_scripts"`
+	N             *uint64         `json:"n"`
+	Slot          *json.Number    `json:"slot"`
+}
+
+var nativeScriptJSONTypes = map[NativeScriptType]string{
+	ScriptPubKey:        "sig",
+	ScriptAll:           "all",
+	ScriptAny:           "any",
+	ScriptNofK:          "atLeast",
+	ScriptInvalidBefore: "after",
+	ScriptInvalidAfter:  "before",
+}
+
+var cslNativeScriptJSONTypes = map[string]NativeScriptType{
+	"ScriptPubkey":   ScriptPubKey,
+	"ScriptAll":      ScriptAll,
+	"ScriptAny":      ScriptAny,
+	"ScriptNOfK":     ScriptNofK,
+	"TimelockStart":  ScriptInvalidBefore,
+	"TimelockExpiry": ScriptInvalidAfter,
+}
+
+// MarshalJSON implements json.Marshaler using the cardano-cli script format.
+func (ns NativeScript) MarshalJSON() ([]byte, error) {
+	scriptType, ok := nativeScriptJSONTypes[ns.Type]
+	if !ok {
+		return nil, fmt.Errorf("invalid native script type %d", ns.Type)
+	}
+
+	script := nativeScriptJSON{Type: scriptType}
+	switch ns.Type {
+	case ScriptPubKey:
+		script.KeyHash = ns.KeyHash.String()
+	case ScriptAll, ScriptAny, ScriptNofK:
+		scripts := ns.Scripts
+		if scripts == nil {
+			scripts = []NativeScript{}
+		}
+		script.Scripts = &scripts
+		if ns.Type == ScriptNofK {
+			script.Required = &ns.N
+		}
+	case ScriptInvalidBefore, ScriptInvalidAfter:
+		script.Slot = &ns.IntervalValue
+	}
+
+	return json.Marshal(script)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both cardano-cli and
+// cardano-serialization-lib script formats are supported.
+func (ns *NativeScript) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if _, ok := obj["type"]; !ok && len(obj) == 1 {
+		return ns.unmarshalCSLJSON(obj)
+	}
+
+	var script nativeScriptJSON
+	if err := json.Unmarshal(data, &script); err != nil {
+		return err
+	}
+
+	*ns = NativeScript{}
+	switch script.Type {
+	case "sig":
+		keyHash, err := newScriptKeyHash(script.KeyHash)
+		if err != nil {
+			return err
+		}
+		ns.Type, ns.KeyHash = ScriptPubKey, keyHash
+	case "all", "any", "atLeast":
+		if script.Scripts == nil {
+			return fmt.Errorf("missing scripts in native script of type %q", script.Type)
+		}
+		if len(*script.Scripts) > 0 {
+			ns.Scripts = *script.Scripts
+		}
+		switch script.Type {
+		case "all":
+			ns.Type = ScriptAll
+		case "any":
+			ns.Type = ScriptAny
+		case "atLeast":
+			if script.Required == nil {
+				return fmt.Errorf("missing required in native script of type %q", script.Type)
+			}
+			ns.Type, ns.N = ScriptNofK, *script.Required
+		}
+	case "after", "before":
+		if script.Slot == nil {
+			return fmt.Errorf("missing slot in native script of type %q", script.Type)
+		}
+		ns.Type, ns.IntervalValue = ScriptInvalidBefore, *script.Slot
+		if script.Type == "before" {
+			ns.Type = ScriptInvalidAfter
+		}
+	default:
+		return fmt.Errorf("invalid native script type %q", script.Type)
+	}
+
+	return nil
+}
+
+func (ns *NativeScript) unmarshalCSLJSON(obj map[string]json.RawMessage) error {
+	for key, value := range obj {
+		scriptType, ok := cslNativeScriptJSONTypes[key]
+		if !ok {
+			return fmt.Errorf("invalid native script type %q", key)
+		}
+
+		var script cslNativeScriptJSON
+		if err := json.Unmarshal(value, &script); err != nil {
+			return err
+		}
+
+		*ns = NativeScript{Type: scriptType}
+		switch scriptType {
+		case ScriptPubKey:
+			if script.AddrKeyHash == nil {
+				return fmt.Errorf("missing addr_keyhash in native script of type %q", key)
+			}
+			keyHash, err := newScriptKeyHash(*script.AddrKeyHash)
+			if err != nil {
+				return err
+			}
+			ns.KeyHash = keyHash
+		case ScriptAll, ScriptAny, ScriptNofK:
+			if script.NativeScripts == nil {
+				return fmt.Errorf("missing native_scripts in native script of type %q", key)
+			}
+			if len(*script.NativeScripts) > 0 {
+				ns.Scripts = *script.NativeScripts
+			}
+			if scriptType == ScriptNofK {
+				if script.N == nil {
+					return fmt.Errorf("missing n in native script of type %q", key)
+				}
+				ns.N = *script.N
+			}
+		case ScriptInvalidBefore, ScriptInvalidAfter:
+			if script.Slot == nil {
+				return fmt.Errorf("missing slot in native script of type %q", key)
+			}
+			slot, err := strconv.ParseUint(script.Slot.String(), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid native script slot %q", script.Slot.String())
+			}
+			ns.IntervalValue = slot
+		}
+	}
+
+	return nil
+}
+
+func newScriptKeyHash(keyHashHex string) (AddrKeyHash, error) {
+	keyHash, err := hex.DecodeString(keyHashHex)
+	if err != nil || len(keyHash) != 28 {
+		return nil, fmt.Errorf("invalid native script key hash %q", keyHashHex)
+	}
+	return keyHash, nil
+}
+
+// Evaluate returns true if the script is satisfied by the signers and the validity
+// interval of the transaction, as in phase-1 validation. Nil bounds are unbounded.
+func (ns *NativeScript) Evaluate(signers []AddrKeyHash, validityStart, ttl Uint64) bool {
+	switch ns.Type {
+	case ScriptPubKey:
+		return containsKeyHash(signers, ns.KeyHash)
+	case ScriptAll:
+		for _, script := range ns.Scripts {
+			if !script.Evaluate(signers, validityStart, ttl) {
+				return false
+			}
+		}
+		return true
+	case ScriptAny:
+		for _, script := range ns.Scripts {
+			if script.Evaluate(signers, validityStart, ttl) {
+				return true
+			}
+		}
+		return false
+	case ScriptNofK:
+		var n uint64
+		for _, script := range ns.Scripts {
+			if script.Evaluate(signers, validityStart, ttl) {
+				n++
+			}
+		}
+		return n >= ns.N
+	case ScriptInvalidBefore:
+		return validityStart != nil && ns.IntervalValue <= *validityStart
+	case ScriptInvalidAfter:
+		return ttl != nil && *ttl <= ns.IntervalValue
+	default:
+		return false
+	}
+}
+
+// MinimalSigners returns a minimal set of key hashes whose signatures satisfy the
+// script, using only the available keys. If available is nil any key can be used.
+func (ns *NativeScript) MinimalSigners(available []AddrKeyHash) ([]AddrKeyHash, error) {
+	req, ok := ns.requirements(available, scriptRequirements{})
+	if !ok {
+		return nil, fmt.Errorf("native script can't be satisfied with the available keys")
+	}
+	return req.signers, nil
+}
+
+// ValidityInterval returns the widest validity interval required by the script when
+// it's satisfied by the signers returned by MinimalSigners. Nil bounds are unbounded.
+func (ns *NativeScript) ValidityInterval(available []AddrKeyHash) (validityStart, ttl Uint64, err error) {
+	req, ok := ns.requirements(available, scriptRequirements{})
+	if !ok {
+		return nil, nil, fmt.Errorf("native script can't be satisfied with the available keys")
+	}
+	return req.validityStart, req.ttl, nil
+}
+
+// scriptRequirements are the signers and validity interval that satisfy a script.
+type scriptRequirements struct {
+	signers       []AddrKeyHash
+	validityStart Uint64
+	ttl           Uint64
+}
+
+// merge adds the requirements of rhs, it returns false if the resulting validity
+// interval is empty.
+func (r *scriptRequirements) merge(rhs scriptRequirements) bool {
+	for _, signer := range rhs.signers {
+		if !containsKeyHash(r.signers, signer) {
+			r.signers = append(r.signers, signer)
+		}
+	}
+	if rhs.validityStart != nil && (r.validityStart == nil || *rhs.validityStart > *r.validityStart) {
+		r.validityStart = rhs.validityStart
+	}
+	if rhs.ttl != nil && (r.ttl == nil || *rhs.ttl < *r.ttl) {
+		r.ttl = rhs.ttl
+	}
+	return r.validityStart == nil || r.ttl == nil || *r.validityStart < *r.ttl
+}
+
+// clone returns a copy of the requirements that can be merged without modifying r.
+func (r scriptRequirements) clone() scriptRequirements {
+	return scriptRequirements{
+		signers:       append([]AddrKeyHash{}, r.signers...),
+		validityStart: r.validityStart,
+		ttl:           r.ttl,
+	}
+}
+
+// narrows returns true if the validity interval of r is narrower than the bounds
+// set in the given interval.
+func (r scriptRequirements) narrows(interval scriptRequirements) bool {
+	if interval.validityStart != nil && r.validityStart != nil && *r.validityStart > *interval.validityStart {
+		return true
+	}
+	return interval.ttl != nil && r.ttl != nil && *r.ttl < *interval.ttl
+}
+
+// better returns true if r is preferred to rhs: requirements that keep the given
+// interval are preferred, then the ones that need fewer signers.
+func (r scriptRequirements) better(rhs scriptRequirements, interval scriptRequirements) bool {
+	if narrows, rhsNarrows := r.narrows(interval), rhs.narrows(interval); narrows != rhsNarrows {
+		return !narrows
+	}
+	return len(r.signers) < len(rhs.signers)
+}
+
+// chooseRequirements searches the combinations of n candidates whose validity
+// intervals overlap the given interval and returns the best one.
+func chooseRequirements(candidates []scriptRequirements, n uint64, interval scriptRequirements) (scriptRequirements, bool) {
+	var best scriptRequirements
+	var found bool
+	var search func(start int, req scriptRequirements, n uint64)
+	search = func(start int, req scriptRequirements, n uint64) {
+		// Merging only adds signers and narrows the interval
+		if found && !req.better(best, interval) {
+			return
+		}
+		if n == 0 {
+			best, found = req, true
+			return
+		}
+		for i := start; uint64(len(candidates)-i) >= n; i++ {
+			next := req.clone()
+			if next.merge(candidates[i]) {
+				search(i+1, next, n-1)
+			}
+		}
+	}
+	search(0, interval.clone(), n)
+	return best, found
+}
+
+// requirements returns the requirements to satisfy the script within the given
+// validity interval, the interval of the result is contained in it. Scripts of type
+// Any and NofK choose the satisfiable sub-scripts that keep the interval, and then
+// the ones that need fewer signers.
+func (ns *NativeScript) requirements(available []AddrKeyHash, interval scriptRequirements) (scriptRequirements, bool) {
+	req := scriptRequirements{validityStart: interval.validityStart, ttl: interval.ttl}
+	switch ns.Type {
+	case ScriptPubKey:
+		if available != nil && !containsKeyHash(available, ns.KeyHash) {
+			return scriptRequirements{}, false
+		}
+		req.signers = []AddrKeyHash{ns.KeyHash}
+		return req, true
+	case ScriptAll:
+		for _, script := range ns.Scripts {
+			scriptReq, ok := script.requirements(available, interval)
+			if !ok || !req.merge(scriptReq) {
+				return scriptRequirements{}, false
+			}
+		}
+		return req, true
+	case ScriptAny, ScriptNofK:
+		n := ns.N
+		if ns.Type == ScriptAny {
+			n = 1
+		}
+		candidates := []scriptRequirements{}
+		for _, script := range ns.Scripts {
+			if scriptReq, ok := script.requirements(available, interval); ok {
+				candidates = append(candidates, scriptReq)
+			}
+		}
+		if uint64(len(candidates)) < n {
+			return scriptRequirements{}, false
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].better(candidates[j], interval)
+		})
+		return chooseRequirements(candidates, n, req)
+	case ScriptInvalidBefore:
+		ok := req.merge(scriptRequirements{validityStart: NewUint64(ns.IntervalValue)})
+		return req, ok
+	case ScriptInvalidAfter:
+		ok := req.merge(scriptRequirements{ttl: NewUint64(ns.IntervalValue)})
+		return req, ok
+	default:
+		return scriptRequirements{}, false
+	}
+}
+
+func containsKeyHash(keyHashes []AddrKeyHash, keyHash AddrKeyHash) bool {
+	for _, kh := range keyHashes {
+		if bytes.Equal(kh, keyHash) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlutusV1Script is a Cardano Plutus V1 script.
+type PlutusV1Script []byte
+
+// Hash returns the script hash using blake2b224.
+func (ps PlutusV1Script) Hash() (Hash28, error) {
+	return hashScript(PlutusV1ScriptNamespace, ps)
+}
+
+// PlutusV2Script is a Cardano Plutus V2 script.
+type PlutusV2Script []byte
+
+// Hash returns the script hash using blake2b224.
+func (ps PlutusV2Script) Hash() (Hash28, error) {
+	return hashScript(PlutusV2ScriptNamespace, ps)
+}
+
+// PlutusV3Script is a Cardano Plutus V3 script.
+type PlutusV3Script []byte
+
+// Hash returns the script hash using blake2b224.
+func (ps PlutusV3Script) Hash() (Hash28, error) {
+	return hashScript(PlutusV3ScriptNamespace, ps)
+}
+
+// hashScript computes the hash of a serialized script prefixed by its namespace.
+func hashScript(namespace ScriptHashNamespace, script []byte) (Hash28, error) {
+	bytes := make([]byte, 0, len(script)+1)
+	bytes = append(bytes, byte(namespace))
+	bytes = append(bytes, script...)
+	return Blake224Hash(bytes)
+}
+
+type ScriptRefType uint64
+
+const (
+	NativeScriptRef ScriptRefType = iota
+	PlutusV1ScriptRef
+	PlutusV2ScriptRef
+	PlutusV3ScriptRef
+)
+
+// ScriptRef is a reference script stored in a transaction output.
+type ScriptRef struct {
+	Type         ScriptRefType
+	NativeScript NativeScript
+	PlutusScript []byte
+}
+
+// NewNativeScriptRef returns a new native ScriptRef.
+func NewNativeScriptRef(script NativeScript) *ScriptRef {
+	return &ScriptRef{Type: NativeScriptRef, NativeScript: script}
+}
+
+// NewPlutusV1ScriptRef returns a new Plutus V1 ScriptRef.
+func NewPlutusV1ScriptRef(script PlutusV1Script) *ScriptRef {
+	return &ScriptRef{Type: PlutusV1ScriptRef, PlutusScript: script}
+}
+
+// NewPlutusV2ScriptRef returns a new Plutus V2 ScriptRef.
+func NewPlutusV2ScriptRef(script PlutusV2Script) *ScriptRef {
+	return &ScriptRef{Type: PlutusV2ScriptRef, PlutusScript: script}
+}
+
+// NewPlutusV3ScriptRef returns a new Plutus V3 ScriptRef.
+func NewPlutusV3ScriptRef(script PlutusV3Script) *ScriptRef {
+	return &ScriptRef{Type: PlutusV3ScriptRef, PlutusScript: script}
+}
+
+// Hash returns the script hash using blake2b224.
+func (s *ScriptRef) Hash() (Hash28, error) {
+	switch s.Type {
+	case NativeScriptRef:
+		return s.NativeScript.Hash()
+	case PlutusV1ScriptRef:
+		return hashScript(PlutusV1ScriptNamespace, s.PlutusScript)
+	case PlutusV2ScriptRef:
+		return hashScript(PlutusV2ScriptNamespace, s.PlutusScript)
+	case PlutusV3ScriptRef:
+		return hashScript(PlutusV3ScriptNamespace, s.PlutusScript)
+	default:
+		return nil, fmt.Errorf("invalid script ref type %d", s.Type)
+	}
+}
+
+// size returns the size in bytes of the script used by the reference scripts fee.
+func (s *ScriptRef) size() uint64 {
+	if s.Type == NativeScriptRef {
+		scriptBytes, err := s.NativeScript.MarshalCBOR()
+		if err != nil {
+			return 0
+		}
+		return uint64(len(scriptBytes))
+	}
+	return uint64(len(s.PlutusScript))
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The script is encoded as a CBOR data item (tag 24).
+func (s *ScriptRef) MarshalCBOR() ([]byte, error) {
+	var script []interface{}
+	switch s.Type {
+	case NativeScriptRef:
+		script = append(script, s.Type, &s.NativeScript)
+	case PlutusV1ScriptRef, PlutusV2ScriptRef, PlutusV3ScriptRef:
+		script = append(script, s.Type, s.PlutusScript)
+	default:
+		return nil, fmt.Errorf("cbor: invalid script ref type %d", s.Type)
+	}
+	bytes, err := cborEnc.Marshal(script)
+	if err != nil {
+		return nil, err
+	}
+	return cborEnc.Marshal(cbor.Tag{Number: 24, Content: bytes})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (s *ScriptRef) UnmarshalCBOR(data []byte) error {
+	content, err := unwrapEncodedCBOR(data)
+	if err != nil {
+		return err
+	}
+
+	scriptType, err := getTypeFromCBORArray(content)
+	if err != nil {
+		return fmt.Errorf("cbor: cannot unmarshal CBOR array into ScriptRef (%v)", err)
+	}
+
+	script := []cbor.RawMessage{}
+	if err := cborDec.Unmarshal(content, &script); err != nil {
+		return err
+	}
+	if len(script) != 2 {
+		return fmt.Errorf("cbor: invalid script ref length %d", len(script))
+	}
+
+	s.Type = ScriptRefType(scriptType)
+	switch s.Type {
+	case NativeScriptRef:
+		return cborDec.Unmarshal(script[1], &s.NativeScript)
+	case PlutusV1ScriptRef, PlutusV2ScriptRef, PlutusV3ScriptRef:
+		return cborDec.Unmarshal(script[1], &s.PlutusScript)
+	default:
+		return fmt.Errorf("cbor: invalid script ref type %d", s.Type)
+	}
+}
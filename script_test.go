@@ -0,0 +1,313 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPlutusScriptHash(t *testing.T) {
+	// Always succeeds script
+	script, err := hex.DecodeString("4d01000033222220051200120011")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name   string
+		script interface{ Hash() (Hash28, error) }
+		hash   string
+	}{
+		{
+			name:   "PlutusV1",
+			script: PlutusV1Script(script),
+			hash:   "67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656",
+		},
+		{
+			name:   "PlutusV2",
+			script: PlutusV2Script(script),
+			hash:   "793f8c8cffba081b2a56462fc219cc8fe652d6a338b62c7b134876e7",
+		},
+		{
+			name:   "PlutusV3",
+			script: PlutusV3Script(script),
+			hash:   "4fff649fb4372ec3c408b6f0468d74e4d319904cde27fd3f00910a52",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := tc.script.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := hash.String(), tc.hash; got != want {
+				t.Errorf("invalid script hash\ngot: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestNativeScriptEvaluate(t *testing.T) {
+	keyA, keyB, keyC := Hash28{0xa}, Hash28{0xb}, Hash28{0xc}
+	sig := func(keyHash AddrKeyHash) NativeScript {
+		return NativeScript{Type: ScriptPubKey, KeyHash: keyHash}
+	}
+	after := NativeScript{Type: ScriptInvalidBefore, IntervalValue: 1000}
+	before := NativeScript{Type: ScriptInvalidAfter, IntervalValue: 5000}
+
+	testcases := []struct {
+		name          string
+		script        NativeScript
+		signers       []AddrKeyHash
+		validityStart Uint64
+		ttl           Uint64
+		want          bool
+	}{
+		{name: "sig", script: sig(keyA), signers: []AddrKeyHash{keyA}, want: true},
+		{name: "missing sig", script: sig(keyA), signers: []AddrKeyHash{keyB}, want: false},
+		{name: "empty all", script: NativeScript{Type: ScriptAll}, want: true},
+		{name: "empty any", script: NativeScript{Type: ScriptAny}, want: false},
+		{
+			name:    "all",
+			script:  NativeScript{Type: ScriptAll, Scripts: []NativeScript{sig(keyA), sig(keyB)}},
+			signers: []AddrKeyHash{keyA},
+			want:    false,
+		},
+		{
+			name:    "any",
+			script:  NativeScript{Type: ScriptAny, Scripts: []NativeScript{sig(keyA), sig(keyB)}},
+			signers: []AddrKeyHash{keyB},
+			want:    true,
+		},
+		{
+			name:    "at least",
+			script:  NativeScript{Type: ScriptNofK, N: 2, Scripts: []NativeScript{sig(keyA), sig(keyB), sig(keyC)}},
+			signers: []AddrKeyHash{keyA, keyC},
+			want:    true,
+		},
+		{
+			name:    "not enough",
+			script:  NativeScript{Type: ScriptNofK, N: 2, Scripts: []NativeScript{sig(keyA), sig(keyB), sig(keyC)}},
+			signers: []AddrKeyHash{keyC},
+			want:    false,
+		},
+		{name: "after without validity start", script: after, want: false},
+		{name: "after too early", script: after, validityStart: NewUint64(999), want: false},
+		{name: "after", script: after, validityStart: NewUint64(1000), want: true},
+		{name: "before without ttl", script: before, want: false},
+		{name: "before too late", script: before, ttl: NewUint64(5001), want: false},
+		{name: "before", script: before, ttl: NewUint64(5000), want: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.script.Evaluate(tc.signers, tc.validityStart, tc.ttl); got != tc.want {
+				t.Errorf("invalid script evaluation\ngot: %v\nwant: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNativeScriptRequirements(t *testing.T) {
+	keyA, keyB, keyC := Hash28{0xa}, Hash28{0xb}, Hash28{0xc}
+	sig := func(keyHash AddrKeyHash) NativeScript {
+		return NativeScript{Type: ScriptPubKey, KeyHash: keyHash}
+	}
+
+	// all [any [A, all [B, C]], after 1000, before 5000] or all [C, after 7000]
+	script := NativeScript{Type: ScriptAny, Scripts: []NativeScript{
+		{Type: ScriptAll, Scripts: []NativeScript{
+			{Type: ScriptAny, Scripts: []NativeScript{
+				{Type: ScriptAll, Scripts: []NativeScript{sig(keyB), sig(keyC)}},
+				sig(keyA),
+			}},
+			{Type: ScriptInvalidBefore, IntervalValue: 1000},
+			{Type: ScriptInvalidAfter, IntervalValue: 5000},
+		}},
+		{Type: ScriptAll, Scripts: []NativeScript{
+			sig(keyC),
+			{Type: ScriptInvalidBefore, IntervalValue: 7000},
+		}},
+	}}
+
+	testcases := []struct {
+		name          string
+		available     []AddrKeyHash
+		signers       []AddrKeyHash
+		validityStart Uint64
+		ttl           Uint64
+		wantErr       bool
+	}{
+		{
+			name:          "any key",
+			signers:       []AddrKeyHash{keyA},
+			validityStart: NewUint64(1000),
+			ttl:           NewUint64(5000),
+		},
+		{
+			name:          "B and C",
+			available:     []AddrKeyHash{keyB, keyC},
+			signers:       []AddrKeyHash{keyC},
+			validityStart: NewUint64(7000),
+		},
+		{
+			name:      "only B",
+			available: []AddrKeyHash{keyB},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			signers, err := script.MinimalSigners(tc.available)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected error")
+			}
+			if !reflect.DeepEqual(signers, tc.signers) {
+				t.Errorf("invalid signers\ngot: %v\nwant: %v", signers, tc.signers)
+			}
+
+			validityStart, ttl, err := script.ValidityInterval(tc.available)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(validityStart, tc.validityStart) || !reflect.DeepEqual(ttl, tc.ttl) {
+				t.Errorf("invalid validity interval\ngot: %v %v\nwant: %v %v", validityStart, ttl, tc.validityStart, tc.ttl)
+			}
+			if !script.Evaluate(signers, validityStart, ttl) {
+				t.Errorf("script not satisfied by its requirements")
+			}
+		})
+	}
+}
+
+func TestNativeScriptRequirementsConflictingIntervals(t *testing.T) {
+	keyA, keyB, keyC, keyD := Hash28{0xa}, Hash28{0xb}, Hash28{0xc}, Hash28{0xd}
+	sig := func(keyHash AddrKeyHash) NativeScript {
+		return NativeScript{Type: ScriptPubKey, KeyHash: keyHash}
+	}
+
+	// 2 of [all [A, after 8000], all [B, before 5000], all [C, D, before 9000]]
+	script := NativeScript{Type: ScriptNofK, N: 2, Scripts: []NativeScript{
+		{Type: ScriptAll, Scripts: []NativeScript{sig(keyA), {Type: ScriptInvalidBefore, IntervalValue: 8000}}},
+		{Type: ScriptAll, Scripts: []NativeScript{sig(keyB), {Type: ScriptInvalidAfter, IntervalValue: 5000}}},
+		{Type: ScriptAll, Scripts: []NativeScript{sig(keyC), sig(keyD), {Type: ScriptInvalidAfter, IntervalValue: 9000}}},
+	}}
+
+	signers, err := script.MinimalSigners(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []AddrKeyHash{keyA, keyC, keyD}; !reflect.DeepEqual(signers, want) {
+		t.Errorf("invalid signers\ngot: %v\nwant: %v", signers, want)
+	}
+	validityStart, ttl, err := script.ValidityInterval(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(validityStart, NewUint64(8000)) || !reflect.DeepEqual(ttl, NewUint64(9000)) {
+		t.Errorf("invalid validity interval\ngot: %v %v\nwant: %v %v", validityStart, ttl, 8000, 9000)
+	}
+	if !script.Evaluate(signers, validityStart, ttl) {
+		t.Errorf("script not satisfied by its requirements")
+	}
+
+	// Without C the only remaining pair has an empty validity interval
+	if _, err := script.MinimalSigners([]AddrKeyHash{keyA, keyB, keyD}); err == nil {
+		t.Error("expected unsatisfiable script error")
+	}
+}
+
+func TestNativeScriptJSON(t *testing.T) {
+	keyHash, err := NewHash28("1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := NativeScript{Type: ScriptPubKey, KeyHash: keyHash}
+
+	testcases := []struct {
+		name    string
+		json    string
+		cslJSON string
+		script  NativeScript
+	}{
+		{
+			name:    "sig",
+			json:    `{"type":"sig","keyHash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"}`,
+			cslJSON: `{"ScriptPubkey":{"addr_keyhash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"}}`,
+			script:  sig,
+		},
+		{
+			name:    "all",
+			json:    `{"type":"all","scripts":[{"type":"sig","keyHash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"},{"type":"before","slot":5000}]}`,
+			cslJSON: `{"ScriptAll":{"native_scripts":[{"ScriptPubkey":{"addr_keyhash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"}},{"TimelockExpiry":{"slot":"5000"}}]}}`,
+			script: NativeScript{Type: ScriptAll, Scripts: []NativeScript{
+				sig,
+				{Type: ScriptInvalidAfter, IntervalValue: 5000},
+			}},
+		},
+		{
+			name:    "any",
+			json:    `{"type":"any","scripts":[{"type":"after","slot":1000}]}`,
+			cslJSON: `{"ScriptAny":{"native_scripts":[{"TimelockStart":{"slot":"1000"}}]}}`,
+			script: NativeScript{Type: ScriptAny, Scripts: []NativeScript{
+				{Type: ScriptInvalidBefore, IntervalValue: 1000},
+			}},
+		},
+		{
+			name:    "atLeast",
+			json:    `{"type":"atLeast","required":1,"scripts":[{"type":"sig","keyHash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"}]}`,
+			cslJSON: `{"ScriptNOfK":{"n":1,"native_scripts":[{"ScriptPubkey":{"addr_keyhash":"1c12f03c1ef2e935acc35ec2e6f96c650fd3bfba3e96550504d53361"}}]}}`,
+			script:  NativeScript{Type: ScriptNofK, N: 1, Scripts: []NativeScript{sig}},
+		},
+		{
+			name:    "empty all",
+			json:    `{"type":"all","scripts":[]}`,
+			cslJSON: `{"ScriptAll":{"native_scripts":[]}}`,
+			script:  NativeScript{Type: ScriptAll},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.json {
+				t.Errorf("invalid script JSON\ngot: %s\nwant: %s", got, tc.json)
+			}
+
+			for _, data := range []string{tc.json, tc.cslJSON} {
+				var script NativeScript
+				if err := json.Unmarshal([]byte(data), &script); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(script, tc.script) {
+					t.Errorf("invalid script\ngot: %+v\nwant: %+v", script, tc.script)
+				}
+			}
+		})
+	}
+
+	for _, data := range []string{
+		`{"type":"sig","keyHash":"1c12"}`,
+		`{"type":"atLeast","scripts":[]}`,
+		`{"type":"before"}`,
+		`{"type":"unknown"}`,
+		`{"ScriptUnknown":{}}`,
+	} {
+		var script NativeScript
+		if err := json.Unmarshal([]byte(data), &script); err == nil {
+			t.Errorf("expected error decoding %s", data)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+// Package textenvelope implements the TextEnvelope file format used by cardano-cli
+// to exchange transactions, witnesses, keys and scripts.
+package textenvelope
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/echovl/cardano-go"
+	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/cbor"
+)
+
+// TextEnvelope is a CBOR encoded value with its type and description.
+type TextEnvelope struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	CborHex     string `json:"cborHex"`
+}
+
+// New returns a new TextEnvelope containing the CBOR encoding of v.
+func New(typ, description string, v interface{}) (*TextEnvelope, error) {
+	cborBytes, err := cbor.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &TextEnvelope{Type: typ, Description: description, CborHex: hex.EncodeToString(cborBytes)}, nil
+}
+
+// Read reads a TextEnvelope in JSON format.
+func Read(r io.Reader) (*TextEnvelope, error) {
+	var env TextEnvelope
+	if err := json.NewDecoder(r).Decode(&env); err != nil {
+		return nil, err
+	}
+	if env.Type == "" {
+		return nil, fmt.Errorf("missing text envelope type")
+	}
+	return &env, nil
+}
+
+// ReadFile reads a TextEnvelope from a file.
+func ReadFile(name string) (*TextEnvelope, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Write writes the TextEnvelope in JSON format, indented as cardano-cli does.
+func (env *TextEnvelope) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(env, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// WriteFile writes the TextEnvelope to a file.
+func (env *TextEnvelope) WriteFile(name string) error {
+	var buf bytes.Buffer
+	if err := env.Write(&buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, buf.Bytes(), 0600)
+}
+
+// Bytes returns the CBOR bytes of the envelope.
+func (env *TextEnvelope) Bytes() ([]byte, error) {
+	return hex.DecodeString(env.CborHex)
+}
+
+func (env *TextEnvelope) unmarshal(v interface{}) error {
+	cborBytes, err := env.Bytes()
+	if err != nil {
+		return err
+	}
+	return cbor.Unmarshal(cborBytes, v)
+}
+
+func (env *TextEnvelope) checkType(types ...string) error {
+	for _, typ := range types {
+		if env.Type == typ {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid text envelope type %q, expected one of %q", env.Type, types)
+}
+
+// Era is a Cardano ledger era.
+type Era uint8
+
+const (
+	ShelleyEra Era = iota
+	AllegraEra
+	MaryEra
+	AlonzoEra
+	BabbageEra
+	ConwayEra
+)
+
+var eras = []Era{ShelleyEra, AllegraEra, MaryEra, AlonzoEra, BabbageEra, ConwayEra}
+
+// String implements Stringer.
+func (e Era) String() string {
+	switch e {
+	case ShelleyEra:
+		return "ShelleyEra"
+	case AllegraEra:
+		return "AllegraEra"
+	case MaryEra:
+		return "MaryEra"
+	case AlonzoEra:
+		return "AlonzoEra"
+	case BabbageEra:
+		return "BabbageEra"
+	case ConwayEra:
+		return "ConwayEra"
+	default:
+		return fmt.Sprintf("Era(%d)", uint8(e))
+	}
+}
+
+const txDescription = "Ledger Cddl Format"
+
+// NewTx returns a new TextEnvelope containing a transaction of the given era.
+// Transactions of eras before Alonzo are encoded without the validity flag.
+func NewTx(tx *cardano.Tx, era Era) (*TextEnvelope, error) {
+	prefix := "Unwitnessed Tx "
+	ws := tx.WitnessSet
+	if len(ws.VKeyWitnessSet) > 0 || len(ws.BootstrapWitnesses) > 0 || len(ws.Scripts) > 0 ||
+		len(ws.PlutusV1Scripts) > 0 || len(ws.PlutusV2Scripts) > 0 || len(ws.PlutusV3Scripts) > 0 ||
+		len(ws.PlutusData) > 0 || len(ws.Redeemers) > 0 {
+		prefix = "Witnessed Tx "
+	}
+
+	if era >= AlonzoEra {
+		return &TextEnvelope{Type: prefix + era.String(), Description: txDescription, CborHex: tx.Hex()}, nil
+	}
+	return New(prefix+era.String(), txDescription, []interface{}{&tx.Body, &tx.WitnessSet, tx.AuxiliaryData})
+}
+
+// Tx returns the transaction of the envelope. All the transaction types of
+// every era are supported.
+func (env *TextEnvelope) Tx() (*cardano.Tx, error) {
+	if err := env.checkType(txTypes()...); err != nil {
+		return nil, err
+	}
+
+	var items []cbor.RawMessage
+	if err := env.unmarshal(&items); err != nil {
+		return nil, err
+	}
+
+	tx := &cardano.Tx{IsValid: true}
+	switch len(items) {
+	case 3:
+		// Shelley, Allegra and Mary transactions
+		if err := tx.Body.UnmarshalCBOR(items[0]); err != nil {
+			return nil, err
+		}
+		if err := tx.WitnessSet.UnmarshalCBOR(items[1]); err != nil {
+			return nil, err
+		}
+		if err := cbor.Unmarshal(items[2], &tx.AuxiliaryData); err != nil {
+			return nil, err
+		}
+	case 4:
+		cborBytes, err := env.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.UnmarshalCBOR(cborBytes); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid transaction array length %d", len(items))
+	}
+
+	return tx, nil
+}
+
+func txTypes() []string {
+	types := []string{"TxSignedShelley"}
+	for _, era := range eras {
+		types = append(types, "Tx "+era.String(), "Witnessed Tx "+era.String(), "Unwitnessed Tx "+era.String())
+	}
+	return types
+}
+
+// keyWitness is the cardano-cli encoding of a witness: [0, vkey witness] or [1, bootstrap witness].
+type keyWitness struct {
+	_       struct{} `cbor:",toarray"`
+	Type    uint64
+	Witness cbor.RawMessage
+}
+
+// NewVKeyWitness returns a new TextEnvelope containing a vkey witness.
+func NewVKeyWitness(witness cardano.VKeyWitness, era Era) (*TextEnvelope, error) {
+	witnessBytes, err := cbor.Marshal(witness)
+	if err != nil {
+		return nil, err
+	}
+	return New("TxWitness "+era.String(), "Key Witness "+era.String(), keyWitness{Type: 0, Witness: witnessBytes})
+}
+
+// NewBootstrapWitness returns a new TextEnvelope containing a bootstrap witness.
+func NewBootstrapWitness(witness cardano.BootstrapWitness, era Era) (*TextEnvelope, error) {
+	witnessBytes, err := cbor.Marshal(witness)
+	if err != nil {
+		return nil, err
+	}
+	return New("TxWitness "+era.String(), "Key BootstrapWitness "+era.String(), keyWitness{Type: 1, Witness: witnessBytes})
+}
+
+// VKeyWitness returns the vkey witness of the envelope.
+func (env *TextEnvelope) VKeyWitness() (cardano.VKeyWitness, error) {
+	witness, err := env.keyWitness(0)
+	if err != nil {
+		return cardano.VKeyWitness{}, err
+	}
+	var vkeyWitness cardano.VKeyWitness
+	if err := cbor.Unmarshal(witness, &vkeyWitness); err != nil {
+		return cardano.VKeyWitness{}, err
+	}
+	return vkeyWitness, nil
+}
+
+// BootstrapWitness returns the bootstrap witness of the envelope.
+func (env *TextEnvelope) BootstrapWitness() (cardano.BootstrapWitness, error) {
+	witness, err := env.keyWitness(1)
+	if err != nil {
+		return cardano.BootstrapWitness{}, err
+	}
+	var bootstrapWitness cardano.BootstrapWitness
+	if err := cbor.Unmarshal(witness, &bootstrapWitness); err != nil {
+		return cardano.BootstrapWitness{}, err
+	}
+	return bootstrapWitness, nil
+}
+
+func (env *TextEnvelope) keyWitness(witnessType uint64) (cbor.RawMessage, error) {
+	types := []string{}
+	for _, era := range eras {
+		types = append(types, "TxWitness "+era.String())
+	}
+	if err := env.checkType(types...); err != nil {
+		return nil, err
+	}
+	var witness keyWitness
+	if err := env.unmarshal(&witness); err != nil {
+		return nil, err
+	}
+	if witness.Type != witnessType {
+		return nil, fmt.Errorf("invalid witness type %d, expected %d", witness.Type, witnessType)
+	}
+	return witness.Witness, nil
+}
+
+// KeyRole is the role of a key.
+type KeyRole uint8
+
+const (
+	PaymentKey KeyRole = iota
+	StakeKey
+	DRepKey
+)
+
+func (r KeyRole) typ(extended, signing bool) string {
+	var typ string
+	switch r {
+	case PaymentKey:
+		typ = "Payment"
+	case StakeKey:
+		typ = "Stake"
+	case DRepKey:
+		typ = "DRep"
+	}
+	if extended {
+		typ += "Extended"
+	}
+	if signing {
+		typ += "SigningKey"
+	} else {
+		typ += "VerificationKey"
+	}
+	if r != DRepKey {
+		typ += "Shelley"
+	}
+	if extended {
+		return typ + "_ed25519_bip32"
+	}
+	return typ + "_ed25519"
+}
+
+func (r KeyRole) description(signing bool) string {
+	var description string
+	switch r {
+	case PaymentKey:
+		description = "Payment"
+	case StakeKey:
+		description = "Stake"
+	case DRepKey:
+		description = "Delegated Representative"
+	}
+	if signing {
+		return description + " Signing Key"
+	}
+	return description + " Verification Key"
+}
+
+var keyRoles = []KeyRole{PaymentKey, StakeKey, DRepKey}
+
+// NewSigningKey returns a new TextEnvelope containing an extended signing key.
+func NewSigningKey(key crypto.XPrvKey, role KeyRole) (*TextEnvelope, error) {
+	if len(key) != 96 {
+		return nil, fmt.Errorf("invalid extended signing key length %d", len(key))
+	}
+	// The extended private key, the public key and the chain code
+	keyBytes := make([]byte, 0, 128)
+	keyBytes = append(keyBytes, key[:64]...)
+	keyBytes = append(keyBytes, key.PubKey()...)
+	keyBytes = append(keyBytes, key[64:]...)
+	return New(role.typ(true, true), role.description(true), keyBytes)
+}
+
+// SigningKey returns the signing key of the envelope. Both normal and extended
+// signing keys are supported.
+func (env *TextEnvelope) SigningKey() (crypto.PrvKey, error) {
+	for _, role := range keyRoles {
+		switch env.Type {
+		case role.typ(false, true):
+			var seed []byte
+			if err := env.unmarshal(&seed); err != nil {
+				return nil, err
+			}
+			if len(seed) != 32 {
+				return nil, fmt.Errorf("invalid signing key length %d", len(seed))
+			}
+			// Expand the ed25519 seed into the extended private key
+			key := sha512.Sum512(seed)
+			key[0] &= 248
+			key[31] &= 127
+			key[31] |= 64
+			return crypto.PrvKey(key[:]), nil
+		case role.typ(true, true):
+			var key []byte
+			if err := env.unmarshal(&key); err != nil {
+				return nil, err
+			}
+			if len(key) != 128 {
+				return nil, fmt.Errorf("invalid extended signing key length %d", len(key))
+			}
+			return crypto.PrvKey(key[:64]), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid signing key type %q", env.Type)
+}
+
+// NewVerificationKey returns a new TextEnvelope containing a verification key.
+func NewVerificationKey(key crypto.PubKey, role KeyRole) (*TextEnvelope, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid verification key length %d", len(key))
+	}
+	return New(role.typ(false, false), role.description(false), []byte(key))
+}
+
+// NewExtendedVerificationKey returns a new TextEnvelope containing an extended verification key.
+func NewExtendedVerificationKey(key crypto.XPubKey, role KeyRole) (*TextEnvelope, error) {
+	if len(key) != 64 {
+		return nil, fmt.Errorf("invalid extended verification key length %d", len(key))
+	}
+	return New(role.typ(true, false), role.description(false), []byte(key))
+}
+
+// VerificationKey returns the verification key of the envelope. Both normal and
+// extended verification keys are supported.
+func (env *TextEnvelope) VerificationKey() (crypto.PubKey, error) {
+	for _, role := range keyRoles {
+		var size int
+		switch env.Type {
+		case role.typ(false, false):
+			size = 32
+		case role.typ(true, false):
+			size = 64
+		default:
+			continue
+		}
+		var key []byte
+		if err := env.unmarshal(&key); err != nil {
+			return nil, err
+		}
+		if len(key) != size {
+			return nil, fmt.Errorf("invalid verification key length %d", len(key))
+		}
+		return crypto.PubKey(key[:32]), nil
+	}
+	return nil, fmt.Errorf("invalid verification key type %q", env.Type)
+}
+
+// NewNativeScript returns a new TextEnvelope containing a native script.
+func NewNativeScript(script cardano.NativeScript) (*TextEnvelope, error) {
+	return New("SimpleScript", "", &script)
+}
+
+// NativeScript returns the native script of the envelope.
+func (env *TextEnvelope) NativeScript() (cardano.NativeScript, error) {
+	if err := env.checkType("SimpleScript", "SimpleScriptV1", "SimpleScriptV2"); err != nil {
+		return cardano.NativeScript{}, err
+	}
+	var script cardano.NativeScript
+	if err := env.unmarshal(&script); err != nil {
+		return cardano.NativeScript{}, err
+	}
+	return script, nil
+}
+
+// NewPlutusV1Script returns a new TextEnvelope containing a Plutus V1 script.
+func NewPlutusV1Script(script cardano.PlutusV1Script) (*TextEnvelope, error) {
+	return New("PlutusScriptV1", "", []byte(script))
+}
+
+// NewPlutusV2Script returns a new TextEnvelope containing a Plutus V2 script.
+func NewPlutusV2Script(script cardano.PlutusV2Script) (*TextEnvelope, error) {
+	return New("PlutusScriptV2", "", []byte(script))
+}
+
+// NewPlutusV3Script returns a new TextEnvelope containing a Plutus V3 script.
+func NewPlutusV3Script(script cardano.PlutusV3Script) (*TextEnvelope, error) {
+	return New("PlutusScriptV3", "", []byte(script))
+}
+
+// PlutusScript returns the Plutus script of the envelope and its version, 1, 2 or 3.
+func (env *TextEnvelope) PlutusScript() ([]byte, int, error) {
+	var version int
+	switch env.Type {
+	case "PlutusScriptV1":
+		version = 1
+	case "PlutusScriptV2":
+		version = 2
+	case "PlutusScriptV3":
+		version = 3
+	default:
+		return nil, 0, fmt.Errorf("invalid plutus script type %q", env.Type)
+	}
+	var script []byte
+	if err := env.unmarshal(&script); err != nil {
+		return nil, 0, err
+	}
+	return script, version, nil
+}
@@ -0,0 +1,284 @@
+package textenvelope
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/echovl/cardano-go"
+	"github.com/echovl/cardano-go/crypto"
+)
+
+func TestTx(t *testing.T) {
+	paymentKey := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+
+	txHash, err := cardano.NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := cardano.NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txBuilder := cardano.NewTxBuilder(&cardano.ProtocolParams{
+		CoinsPerUTXOWord: 34482,
+		MinFeeA:          44,
+		MinFeeB:          155381,
+	})
+	txBuilder.AddInputs(cardano.NewTxInput(txHash, 0, cardano.NewValue(1e9)))
+	txBuilder.AddOutputs(cardano.NewTxOutput(addr, cardano.NewValue(10e6)))
+	txBuilder.SetTTL(100000)
+	txBuilder.AddChangeIfNeeded(addr)
+
+	txBuilder.Sign(paymentKey.PrvKey())
+	signedTx, err := txBuilder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedTx := &cardano.Tx{Body: signedTx.Body, IsValid: true}
+
+	testcases := []struct {
+		name     string
+		tx       *cardano.Tx
+		era      Era
+		wantType string
+	}{
+		{name: "Unwitnessed Alonzo", tx: unsignedTx, era: AlonzoEra, wantType: "Unwitnessed Tx AlonzoEra"},
+		{name: "Witnessed Babbage", tx: signedTx, era: BabbageEra, wantType: "Witnessed Tx BabbageEra"},
+		{name: "Witnessed Conway", tx: signedTx, era: ConwayEra, wantType: "Witnessed Tx ConwayEra"},
+		{name: "Witnessed Mary", tx: signedTx, era: MaryEra, wantType: "Witnessed Tx MaryEra"},
+		{name: "Witnessed Shelley", tx: signedTx, era: ShelleyEra, wantType: "Witnessed Tx ShelleyEra"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := NewTx(tc.tx, tc.era)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if env.Type != tc.wantType {
+				t.Errorf("invalid type\ngot: %s\nwant: %s", env.Type, tc.wantType)
+			}
+
+			var buf bytes.Buffer
+			if err := env.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			decodedEnv, err := Read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decodedTx, err := decodedEnv.Tx()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotHash, err := decodedTx.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantHash, err := tc.tx.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotHash.String() != wantHash.String() {
+				t.Errorf("invalid tx hash\ngot: %s\nwant: %s", gotHash, wantHash)
+			}
+			if !reflect.DeepEqual(decodedTx.WitnessSet.VKeyWitnessSet, tc.tx.WitnessSet.VKeyWitnessSet) {
+				t.Errorf("invalid witnesses\ngot: %+v\nwant: %+v", decodedTx.WitnessSet.VKeyWitnessSet, tc.tx.WitnessSet.VKeyWitnessSet)
+			}
+		})
+	}
+
+	if _, err := (&TextEnvelope{Type: "PlutusScriptV1", CborHex: unsignedTx.Hex()}).Tx(); err == nil {
+		t.Error("expected invalid type error")
+	}
+}
+
+func TestWitness(t *testing.T) {
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	prvKey := key.PrvKey()
+	vkeyWitness := cardano.VKeyWitness{VKey: key.PubKey(), Signature: prvKey.Sign([]byte("message"))}
+
+	env, err := NewVKeyWitness(vkeyWitness, BabbageEra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Type != "TxWitness BabbageEra" {
+		t.Errorf("invalid type\ngot: %s\nwant: %s", env.Type, "TxWitness BabbageEra")
+	}
+	gotVKeyWitness, err := env.VKeyWitness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotVKeyWitness, vkeyWitness) {
+		t.Errorf("invalid vkey witness\ngot: %+v\nwant: %+v", gotVKeyWitness, vkeyWitness)
+	}
+	if _, err := env.BootstrapWitness(); err == nil {
+		t.Error("expected invalid witness type error")
+	}
+
+	bootstrapWitness := cardano.BootstrapWitness{
+		VKey:       key.PubKey(),
+		Signature:  prvKey.Sign([]byte("message")),
+		ChainCode:  key[64:],
+		Attributes: []byte{0xa0},
+	}
+	env, err = NewBootstrapWitness(bootstrapWitness, ConwayEra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBootstrapWitness, err := env.BootstrapWitness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotBootstrapWitness, bootstrapWitness) {
+		t.Errorf("invalid bootstrap witness\ngot: %+v\nwant: %+v", gotBootstrapWitness, bootstrapWitness)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	// Normal signing keys are ed25519 seeds
+	seed := bytes.Repeat([]byte{0x01}, 32)
+	env := &TextEnvelope{
+		Type:        "PaymentSigningKeyShelley_ed25519",
+		Description: "Payment Signing Key",
+		CborHex:     "5820" + hex.EncodeToString(seed),
+	}
+	prvKey, err := env.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPubKey := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if got := prvKey.PubKey(); !bytes.Equal(got, wantPubKey) {
+		t.Errorf("invalid public key\ngot: %x\nwant: %x", got, wantPubKey)
+	}
+
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+
+	testcases := []struct {
+		role        KeyRole
+		signingType string
+		verifyType  string
+		xverifyType string
+	}{
+		{
+			role:        PaymentKey,
+			signingType: "PaymentExtendedSigningKeyShelley_ed25519_bip32",
+			verifyType:  "PaymentVerificationKeyShelley_ed25519",
+			xverifyType: "PaymentExtendedVerificationKeyShelley_ed25519_bip32",
+		},
+		{
+			role:        StakeKey,
+			signingType: "StakeExtendedSigningKeyShelley_ed25519_bip32",
+			verifyType:  "StakeVerificationKeyShelley_ed25519",
+			xverifyType: "StakeExtendedVerificationKeyShelley_ed25519_bip32",
+		},
+		{
+			role:        DRepKey,
+			signingType: "DRepExtendedSigningKey_ed25519_bip32",
+			verifyType:  "DRepVerificationKey_ed25519",
+			xverifyType: "DRepExtendedVerificationKey_ed25519_bip32",
+		},
+	}
+
+	for _, tc := range testcases {
+		skeyEnv, err := NewSigningKey(key, tc.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if skeyEnv.Type != tc.signingType {
+			t.Errorf("invalid signing key type\ngot: %s\nwant: %s", skeyEnv.Type, tc.signingType)
+		}
+		gotPrvKey, err := skeyEnv.SigningKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(gotPrvKey, key.PrvKey()) {
+			t.Errorf("invalid signing key\ngot: %x\nwant: %x", gotPrvKey, key.PrvKey())
+		}
+
+		vkeyEnv, err := NewVerificationKey(key.PubKey(), tc.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xvkeyEnv, err := NewExtendedVerificationKey(key.XPubKey(), tc.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vkeyEnv.Type != tc.verifyType {
+			t.Errorf("invalid verification key type\ngot: %s\nwant: %s", vkeyEnv.Type, tc.verifyType)
+		}
+		if xvkeyEnv.Type != tc.xverifyType {
+			t.Errorf("invalid extended verification key type\ngot: %s\nwant: %s", xvkeyEnv.Type, tc.xverifyType)
+		}
+		for _, env := range []*TextEnvelope{vkeyEnv, xvkeyEnv} {
+			gotPubKey, err := env.VerificationKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(gotPubKey, key.PubKey()) {
+				t.Errorf("invalid verification key\ngot: %x\nwant: %x", gotPubKey, key.PubKey())
+			}
+		}
+	}
+}
+
+func TestScripts(t *testing.T) {
+	key := crypto.NewXPrvKeyFromEntropy([]byte("policy"), "")
+	script, err := cardano.NewScriptPubKey(key.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := NewNativeScript(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotScript, err := env.NativeScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotScript, script) {
+		t.Errorf("invalid native script\ngot: %+v\nwant: %+v", gotScript, script)
+	}
+
+	plutusScript := []byte{0x4e, 0x4d, 0x01, 0x00, 0x00, 0x33, 0x22, 0x22, 0x20, 0x05, 0x12, 0x00, 0x12, 0x00, 0x11}
+	env, err = NewPlutusV2Script(plutusScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "4f" + hex.EncodeToString(plutusScript); env.CborHex != want {
+		t.Errorf("invalid plutus script encoding\ngot: %s\nwant: %s", env.CborHex, want)
+	}
+	gotPlutusScript, version, err := env.PlutusScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 || !bytes.Equal(gotPlutusScript, plutusScript) {
+		t.Errorf("invalid plutus script\ngot: %d %x\nwant: %d %x", version, gotPlutusScript, 2, plutusScript)
+	}
+}
+
+func TestFile(t *testing.T) {
+	env := &TextEnvelope{
+		Type:        "PaymentVerificationKeyShelley_ed25519",
+		Description: "Payment Verification Key",
+		CborHex:     "5820" + hex.EncodeToString(bytes.Repeat([]byte{0x02}, 32)),
+	}
+	name := filepath.Join(t.TempDir(), "payment.vkey")
+	if err := env.WriteFile(name); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, env) {
+		t.Errorf("invalid text envelope\ngot: %+v\nwant: %+v", got, env)
+	}
+}
@@ -0,0 +1,167 @@
+package cardano
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// AssetNameLabel is a CIP-67 asset name label.
+type AssetNameLabel uint16
+
+// CIP-68 asset name labels.
+const (
+	ReferenceTokenLabel AssetNameLabel = 100
+	NFTTokenLabel       AssetNameLabel = 222
+	FTTokenLabel        AssetNameLabel = 333
+	RFTTokenLabel       AssetNameLabel = 444
+)
+
+// assetNameLabelSize is the size of a CIP-67 asset name label prefix.
+const assetNameLabelSize = 4
+
+// maxAssetNameSize is the maximum size of an asset name.
+const maxAssetNameSize = 32
+
+// NewLabeledAssetName returns a new AssetName prefixed by a CIP-67 label.
+func NewLabeledAssetName(label AssetNameLabel, name []byte) AssetName {
+	return NewAssetNameFromBytes(append(label.prefix(), name...))
+}
+
+// Label returns the CIP-67 label of the asset name and the name without the
+// label prefix. It returns false if the asset name doesn't have a valid label.
+func (an *AssetName) Label() (AssetNameLabel, []byte, bool) {
+	b := an.Bytes()
+	if len(b) < assetNameLabelSize || b[0]&0xf0 != 0 || b[3]&0x0f != 0 {
+		return 0, nil, false
+	}
+	label := AssetNameLabel(uint16(b[0])<<12 | uint16(b[1])<<4 | uint16(b[2])>>4)
+	if b[2]&0x0f != label.checksum()>>4 || b[3]>>4 != label.checksum()&0x0f {
+		return 0, nil, false
+	}
+	return label, b[assetNameLabelSize:], true
+}
+
+// prefix returns the 4 bytes prefix of the label: [0000 | 16 bits label | 8 bits CRC-8 | 0000].
+func (l AssetNameLabel) prefix() []byte {
+	crc := l.checksum()
+	return []byte{
+		byte(l >> 12),
+		byte(l >> 4),
+		byte(l)<<4 | crc>>4,
+		crc << 4,
+	}
+}
+
+// checksum computes the CRC-8 (polynomial 0x07) of the label.
+func (l AssetNameLabel) checksum() byte {
+	var crc byte
+	for _, b := range []byte{byte(l >> 8), byte(l)} {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// TokenMetadataDatum is the CIP-68 metadata datum attached to the reference token.
+type TokenMetadataDatum struct {
+	Metadata []PlutusDataPair
+	Version  uint64
+	Extra    PlutusData
+}
+
+// Set sets a metadata field, field names are encoded as UTF-8 bytes.
+func (d *TokenMetadataDatum) Set(key string, value PlutusData) *TokenMetadataDatum {
+	for i, pair := range d.Metadata {
+		if pair.Key.Type == BytesData && string(pair.Key.Bytes) == key {
+			d.Metadata[i].Value = value
+			return d
+		}
+	}
+	d.Metadata = append(d.Metadata, PlutusDataPair{Key: NewBytesData([]byte(key)), Value: value})
+	return d
+}
+
+// PlutusData returns the datum as PlutusData: Constr 0 [metadata, version, extra].
+func (d *TokenMetadataDatum) PlutusData() PlutusData {
+	return NewConstrData(0,
+		NewMapData(d.Metadata...),
+		NewIntegerData(new(big.Int).SetUint64(d.Version)),
+		d.Extra,
+	)
+}
+
+// NewTokenMetadataDatum returns a new TokenMetadataDatum from PlutusData.
+func NewTokenMetadataDatum(data PlutusData) (TokenMetadataDatum, error) {
+	if data.Type != ConstrData || data.Constructor != 0 || len(data.Fields) < 2 {
+		return TokenMetadataDatum{}, errors.New("invalid token metadata datum")
+	}
+	metadata, version := data.Fields[0], data.Fields[1]
+	if metadata.Type != MapData {
+		return TokenMetadataDatum{}, errors.New("token metadata must be a map")
+	}
+	if version.Type != IntegerData || version.Integer == nil || !version.Integer.IsUint64() {
+		return TokenMetadataDatum{}, errors.New("invalid token metadata version")
+	}
+
+	datum := TokenMetadataDatum{
+		Metadata: metadata.Map,
+		Version:  version.Integer.Uint64(),
+	}
+	if len(data.Fields) > 2 {
+		datum.Extra = data.Fields[2]
+	}
+
+	return datum, nil
+}
+
+// ReferenceToken is a CIP-68 token pair: the (100) reference NFT, locked with
+// the metadata datum, and the user tokens.
+type ReferenceToken struct {
+	PolicyID PolicyID
+	Name     []byte         // asset name without label
+	Label    AssetNameLabel // NFTTokenLabel, FTTokenLabel or RFTTokenLabel
+	Quantity uint64         // amount of user tokens, must be 1 for NFTs
+	Datum    TokenMetadataDatum
+
+	ReferenceAddress Address // receiver of the reference NFT
+	UserAddress      Address // receiver of the user tokens
+}
+
+// ReferenceAssetName returns the asset name of the (100) reference NFT.
+func (rt *ReferenceToken) ReferenceAssetName() AssetName {
+	return NewLabeledAssetName(ReferenceTokenLabel, rt.Name)
+}
+
+// UserAssetName returns the asset name of the user tokens.
+func (rt *ReferenceToken) UserAssetName() AssetName {
+	return NewLabeledAssetName(rt.Label, rt.Name)
+}
+
+func (rt *ReferenceToken) validate() error {
+	switch rt.Label {
+	case NFTTokenLabel:
+		if rt.Quantity != 1 {
+			return fmt.Errorf("invalid NFT quantity %d, must be 1", rt.Quantity)
+		}
+	case FTTokenLabel, RFTTokenLabel:
+		if rt.Quantity == 0 {
+			return errors.New("invalid token quantity 0")
+		}
+	default:
+		return fmt.Errorf("invalid user token label %d", rt.Label)
+	}
+	if len(rt.Name)+assetNameLabelSize > maxAssetNameSize {
+		return fmt.Errorf("asset name too long, must be at most %d bytes", maxAssetNameSize-assetNameLabelSize)
+	}
+	if len(rt.PolicyID.Bytes()) == 0 {
+		return errors.New("missing policy id")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/echovl/cardano-go/crypto"
+)
+
+func TestAssetNameLabel(t *testing.T) {
+	testcases := []struct {
+		label  AssetNameLabel
+		prefix string
+	}{
+		{label: 0, prefix: "00000000"},
+		{label: 1, prefix: "00001070"},
+		{label: 23, prefix: "00017650"},
+		{label: ReferenceTokenLabel, prefix: "000643b0"},
+		{label: NFTTokenLabel, prefix: "000de140"},
+		{label: FTTokenLabel, prefix: "0014df10"},
+		{label: RFTTokenLabel, prefix: "001bc280"},
+		{label: 65535, prefix: "0ffff240"},
+	}
+
+	for _, tc := range testcases {
+		assetName := NewLabeledAssetName(tc.label, []byte("token"))
+		if got, want := hex.EncodeToString(assetName.Bytes()), tc.prefix+hex.EncodeToString([]byte("token")); got != want {
+			t.Errorf("invalid asset name\ngot: %s\nwant: %s", got, want)
+		}
+
+		label, name, ok := assetName.Label()
+		if !ok {
+			t.Fatalf("label %d not found", tc.label)
+		}
+		if label != tc.label || string(name) != "token" {
+			t.Errorf("invalid label\ngot: %d %s\nwant: %d %s", label, name, tc.label, "token")
+		}
+	}
+
+	// Empty, unlabeled, invalid checksum and invalid padding asset names
+	for _, nameHex := range []string{"", "746f6b656e", "000643b1", "100643b0"} {
+		name, err := hex.DecodeString(nameHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assetName := NewAssetNameFromBytes(name)
+		if _, _, ok := assetName.Label(); ok {
+			t.Errorf("unexpected label in asset name %s", nameHex)
+		}
+	}
+}
+
+func TestTokenMetadataDatum(t *testing.T) {
+	datum := &TokenMetadataDatum{Version: 1}
+	datum.Set("name", NewBytesData([]byte("Token"))).
+		Set("image", NewBytesData([]byte("ipfs://image"))).
+		Set("name", NewBytesData([]byte("Token 1")))
+
+	data := datum.PlutusData()
+	bytes, err := data.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHex := "d8799fa2446e616d6547546f6b656e203145696d6167654c697066733a2f2f696d61676501d87980ff"
+	if got := hex.EncodeToString(bytes); got != wantHex {
+		t.Errorf("invalid datum encoding\ngot: %s\nwant: %s", got, wantHex)
+	}
+
+	var decoded PlutusData
+	if err := decoded.UnmarshalCBOR(bytes); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewTokenMetadataDatum(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Metadata, datum.Metadata) || got.Version != datum.Version {
+		t.Errorf("invalid datum\ngot: %+v\nwant: %+v", got, datum)
+	}
+}
+
+func TestMintReferenceToken(t *testing.T) {
+	paymentKey := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	policyKey := crypto.NewXPrvKeyFromEntropy([]byte("policy"), "")
+
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyScript, err := NewScriptPubKey(policyKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID, err := NewPolicyID(policyScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name     string
+		label    AssetNameLabel
+		quantity uint64
+		version  uint64
+		wantErr  bool
+	}{
+		{name: "NFT", label: NFTTokenLabel, quantity: 1, version: 1},
+		{name: "FT", label: FTTokenLabel, quantity: 1000, version: 1},
+		{name: "RFT", label: RFTTokenLabel, quantity: 10, version: 3},
+		{name: "NFT with quantity", label: NFTTokenLabel, quantity: 2, wantErr: true},
+		{name: "Reference label", label: ReferenceTokenLabel, quantity: 1, wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(alonzoProtocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(1e9)))
+
+			token := ReferenceToken{
+				PolicyID:         policyID,
+				Name:             []byte("cardanogo"),
+				Label:            tc.label,
+				Quantity:         tc.quantity,
+				ReferenceAddress: addr,
+				UserAddress:      addr,
+			}
+			token.Datum.Set("name", NewBytesData([]byte("cardano-go")))
+
+			err := txBuilder.MintReferenceToken(token)
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected error")
+			}
+
+			txBuilder.AddNativeScript(policyScript)
+			txBuilder.Sign(paymentKey.PrvKey())
+			txBuilder.Sign(policyKey.PrvKey())
+			txBuilder.AddChangeIfNeeded(addr)
+			tx, err := txBuilder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decodedTx := &Tx{}
+			if err := decodedTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+
+			mintAssets := decodedTx.Body.Mint.Get(policyID)
+			refName, userName := token.ReferenceAssetName(), token.UserAssetName()
+			if got := mintAssets.Get(refName); got == nil || got.Int64() != 1 {
+				t.Errorf("invalid reference token mint\ngot: %v\nwant: %v", got, 1)
+			}
+			if got := mintAssets.Get(userName); got == nil || got.Uint64() != tc.quantity {
+				t.Errorf("invalid user token mint\ngot: %v\nwant: %v", got, tc.quantity)
+			}
+
+			// The change output is the first output
+			refOutput, userOutput := decodedTx.Body.Outputs[1], decodedTx.Body.Outputs[2]
+			if got := refOutput.Amount.MultiAsset.Get(policyID).Get(refName); got != 1 {
+				t.Errorf("invalid reference output amount\ngot: %v\nwant: %v", got, 1)
+			}
+			if got := userOutput.Amount.MultiAsset.Get(policyID).Get(userName); uint64(got) != tc.quantity {
+				t.Errorf("invalid user output amount\ngot: %v\nwant: %v", got, tc.quantity)
+			}
+			if refOutput.Datum == nil {
+				t.Fatal("missing reference output datum")
+			}
+			datum, err := NewTokenMetadataDatum(*refOutput.Datum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if datum.Version != tc.version {
+				t.Errorf("invalid datum version\ngot: %v\nwant: %v", datum.Version, tc.version)
+			}
+			if !reflect.DeepEqual(datum.Metadata, token.Datum.Metadata) {
+				t.Errorf("invalid datum metadata\ngot: %+v\nwant: %+v", datum.Metadata, token.Datum.Metadata)
+			}
+		})
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 
 	"github.com/echovl/cardano-go/crypto"
+	"github.com/echovl/cardano-go/internal/cbor"
 	"golang.org/x/crypto/blake2b"
 )
 
-const utxoEntrySizeWithoutVal = 27
+const (
+	utxoEntrySizeWithoutVal = 27
+	dataHashSize            = 10
+	utxoEntrySizeBabbage    = 160
+)
 
 // UTxO is a Cardano Unspent Transaction Output.
 type UTxO struct {
@@ -71,8 +76,172 @@ func (tx *Tx) MarshalCBOR() ([]byte, error) {
 
 // WitnessSet represents the witnesses of the transaction.
 type WitnessSet struct {
-	VKeyWitnessSet []VKeyWitness  `cbor:"0,keyasint,omitempty"`
-	Scripts        []NativeScript `cbor:"1,keyasint,omitempty"`
+	VKeyWitnessSet     []VKeyWitness      `cbor:"0,keyasint,omitempty"`
+	Scripts            []NativeScript     `cbor:"1,keyasint,omitempty"`
+	BootstrapWitnesses []BootstrapWitness `cbor:"2,keyasint,omitempty"`
+	PlutusV1Scripts    []PlutusV1Script   `cbor:"3,keyasint,omitempty"`
+	PlutusData         []PlutusData       `cbor:"4,keyasint,omitempty"`
+	Redeemers          Redeemers          `cbor:"5,keyasint,omitempty"`
+	PlutusV2Scripts    []PlutusV2Script   `cbor:"6,keyasint,omitempty"`
+	PlutusV3Scripts    []PlutusV3Script   `cbor:"7,keyasint,omitempty"`
+
+	// redeemersMap is true when the redeemers were decoded from the map format.
+	redeemersMap bool
+	// fields are the original encodings of the decoded fields, indexed by key. They're
+	// kept when other fields are modified, e.g. when vkey witnesses are added to a
+	// decoded transaction, so the script data hash stays valid.
+	fields   map[uint64]originalCBOR
+	original originalCBOR
+}
+
+const (
+	// witnessSetPlutusDataKey is the witness set key of the datums.
+	witnessSetPlutusDataKey = 4
+	// witnessSetRedeemersKey is the witness set key of the redeemers.
+	witnessSetRedeemersKey = 5
+)
+
+// MarshalCBOR implements cbor.Marshaler.
+// The original encoding is used for decoded witness sets that weren't modified.
+func (ws *WitnessSet) MarshalCBOR() ([]byte, error) {
+	bytes, err := ws.marshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	return ws.original.bytes(bytes), nil
+}
+
+// marshalCBOR encodes the witness set with the redeemers in the format they were
+// decoded and the unmodified fields in their original encoding.
+func (ws *WitnessSet) marshalCBOR() ([]byte, error) {
+	type rawWitnessSet WitnessSet
+	bytes, err := cborEnc.Marshal((*rawWitnessSet)(ws))
+	if err != nil {
+		return nil, err
+	}
+	if !ws.redeemersMap && ws.fields == nil {
+		return bytes, nil
+	}
+
+	pairs, err := getMapPairsFromCBOR(bytes)
+	if err != nil {
+		return nil, err
+	}
+	bytes = encodeCBORHead(5, uint64(len(pairs)))
+	for _, pair := range pairs {
+		key, err := witnessSetKey(pair[0])
+		if err != nil {
+			return nil, err
+		}
+		value := ws.fieldBytes(key, pair[1])
+		if key == witnessSetRedeemersKey {
+			if value, err = ws.redeemersBytes(); err != nil {
+				return nil, err
+			}
+		}
+		bytes = append(bytes, pair[0]...)
+		bytes = append(bytes, value...)
+	}
+	return bytes, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (ws *WitnessSet) UnmarshalCBOR(data []byte) error {
+	type rawWitnessSet WitnessSet
+	var rw rawWitnessSet
+	if err := cborDec.Unmarshal(data, &rw); err != nil {
+		return err
+	}
+	pairs, err := getMapPairsFromCBOR(data)
+	if err != nil {
+		return err
+	}
+	rawFields := make(map[uint64][]byte, len(pairs))
+	for _, pair := range pairs {
+		key, err := witnessSetKey(pair[0])
+		if err != nil {
+			return err
+		}
+		if key == witnessSetRedeemersKey {
+			rw.redeemersMap = isRedeemersMap(pair[1])
+		}
+		rawFields[key] = pair[1]
+	}
+
+	*ws = WitnessSet(rw)
+	encoded, err := ws.marshalCBOR()
+	if err != nil {
+		return err
+	}
+	encodedPairs, err := getMapPairsFromCBOR(encoded)
+	if err != nil {
+		return err
+	}
+	for _, pair := range encodedPairs {
+		key, err := witnessSetKey(pair[0])
+		if err != nil {
+			return err
+		}
+		original := newOriginalCBOR(rawFields[key], pair[1])
+		if original.raw == nil {
+			continue
+		}
+		if ws.fields == nil {
+			ws.fields = make(map[uint64]originalCBOR)
+		}
+		ws.fields[key] = original
+	}
+
+	if ws.fields != nil {
+		if encoded, err = ws.marshalCBOR(); err != nil {
+			return err
+		}
+	}
+	ws.original = newOriginalCBOR(data, encoded)
+	return nil
+}
+
+// fieldBytes returns the original encoding of a field if it wasn't modified since
+// it was decoded, otherwise it returns the given encoding.
+func (ws *WitnessSet) fieldBytes(key uint64, encoded []byte) []byte {
+	original, ok := ws.fields[key]
+	if !ok {
+		return encoded
+	}
+	return original.bytes(encoded)
+}
+
+// redeemersBytes returns the encoding of the redeemers in the format they were decoded,
+// the array format is used by default.
+func (ws *WitnessSet) redeemersBytes() ([]byte, error) {
+	var bytes []byte
+	var err error
+	if ws.redeemersMap {
+		bytes, err = ws.Redeemers.mapBytes()
+	} else {
+		bytes, err = cborEnc.Marshal(ws.Redeemers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ws.fieldBytes(witnessSetRedeemersKey, bytes), nil
+}
+
+// plutusDataBytes returns the encoding of the datums.
+func (ws *WitnessSet) plutusDataBytes() ([]byte, error) {
+	bytes, err := cborEnc.Marshal(ws.PlutusData)
+	if err != nil {
+		return nil, err
+	}
+	return ws.fieldBytes(witnessSetPlutusDataKey, bytes), nil
+}
+
+func witnessSetKey(data []byte) (uint64, error) {
+	var key uint64
+	if err := cborDec.Unmarshal(data, &key); err != nil {
+		return 0, fmt.Errorf("invalid witness set key: %w", err)
+	}
+	return key, nil
 }
 
 // VKeyWitness is a witnesss that uses verification keys.
@@ -82,12 +251,23 @@ type VKeyWitness struct {
 	Signature []byte        // ed25519 signature
 }
 
+// BootstrapWitness is a witness used to spend outputs locked by Byron addresses.
+type BootstrapWitness struct {
+	_          struct{}      `cbor:",toarray"`
+	VKey       crypto.PubKey // ed25519 public key
+	Signature  []byte        // ed25519 signature
+	ChainCode  []byte        // chain code of the extended public key
+	Attributes []byte        // CBOR encoded attributes of the Byron address
+}
+
 // TxInput is the transaction input.
 type TxInput struct {
-	_      struct{} `cbor:",toarray"`
-	TxHash Hash32
-	Index  uint64
-	Amount *Value `cbor:"-"`
+	_         struct{} `cbor:",toarray"`
+	TxHash    Hash32
+	Index     uint64
+	Amount    *Value     `cbor:"-"`
+	ScriptRef *ScriptRef `cbor:"-"` // reference script of the spent output, if any
+	Address   *Address   `cbor:"-"` // address of the spent output, if known
 }
 
 // NewTxInput creates a new instance of TxInput
@@ -100,11 +280,24 @@ func (t TxInput) String() string {
 	return fmt.Sprintf("{TxHash: %v, Index: %v, Amount: %v}", t.TxHash, t.Index, t.Amount)
 }
 
-// TxInput is the transaction output.
+// TxOutput is the transaction output.
+// Outputs are encoded using the legacy array format unless they carry an inline datum
+// or a reference script, or they were decoded from the post-Alonzo map format.
 type TxOutput struct {
-	_       struct{} `cbor:",toarray"`
-	Address Address
-	Amount  *Value
+	Address   Address
+	Amount    *Value
+	DatumHash Hash32      // or nil
+	Datum     *PlutusData // inline datum, or nil
+	ScriptRef *ScriptRef  // or nil
+
+	postAlonzo bool
+}
+
+type postAlonzoTxOutput struct {
+	Address   Address      `cbor:"0,keyasint"`
+	Amount    *Value       `cbor:"1,keyasint"`
+	Datum     *datumOption `cbor:"2,keyasint,omitempty"`
+	ScriptRef *ScriptRef   `cbor:"3,keyasint,omitempty"`
 }
 
 // NewTxOutput creates a new instance of TxOutput
@@ -112,27 +305,202 @@ func NewTxOutput(addr Address, amount *Value) *TxOutput {
 	return &TxOutput{Address: addr, Amount: amount}
 }
 
+// IsPostAlonzo returns true if the output is encoded using the post-Alonzo map format.
+func (t *TxOutput) IsPostAlonzo() bool {
+	return t.postAlonzo || t.Datum != nil || t.ScriptRef != nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (t *TxOutput) MarshalCBOR() ([]byte, error) {
+	if t.IsPostAlonzo() {
+		out := postAlonzoTxOutput{
+			Address:   t.Address,
+			Amount:    t.Amount,
+			ScriptRef: t.ScriptRef,
+		}
+		if t.Datum != nil {
+			out.Datum = &datumOption{Type: InlineDatumOption, Data: t.Datum}
+		} else if t.DatumHash != nil {
+			out.Datum = &datumOption{Type: DatumHashOption, Hash: t.DatumHash}
+		}
+		return cborEnc.Marshal(out)
+	}
+
+	out := []interface{}{&t.Address, t.Amount}
+	if t.DatumHash != nil {
+		out = append(out, t.DatumHash)
+	}
+	return cborEnc.Marshal(out)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (t *TxOutput) UnmarshalCBOR(data []byte) error {
+	major, _, _, _, err := decodeCBORHead(data)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case 4:
+		items := []cbor.RawMessage{}
+		if err := cborDec.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		if len(items) != 2 && len(items) != 3 {
+			return fmt.Errorf("cbor: invalid TxOutput array length %d", len(items))
+		}
+		*t = TxOutput{Amount: &Value{}}
+		if err := cborDec.Unmarshal(items[0], &t.Address); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(items[1], t.Amount); err != nil {
+			return err
+		}
+		if len(items) == 3 {
+			if err := cborDec.Unmarshal(items[2], &t.DatumHash); err != nil {
+				return err
+			}
+		}
+	case 5:
+		out := postAlonzoTxOutput{}
+		if err := cborDec.Unmarshal(data, &out); err != nil {
+			return err
+		}
+		*t = TxOutput{
+			Address:    out.Address,
+			Amount:     out.Amount,
+			ScriptRef:  out.ScriptRef,
+			postAlonzo: true,
+		}
+		if out.Datum != nil {
+			switch out.Datum.Type {
+			case DatumHashOption:
+				t.DatumHash = out.Datum.Hash
+			case InlineDatumOption:
+				t.Datum = out.Datum.Data
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: cannot unmarshal CBOR major type %d into TxOutput", major)
+	}
+
+	return nil
+}
+
 func (t TxOutput) String() string {
 	return fmt.Sprintf("{Address: %v, Amount: %v}", t.Address, t.Amount)
 }
 
+type DatumOptionType uint64
+
+const (
+	DatumHashOption DatumOptionType = iota
+	InlineDatumOption
+)
+
+type datumOption struct {
+	Type DatumOptionType
+	Hash Hash32
+	Data *PlutusData
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (d *datumOption) MarshalCBOR() ([]byte, error) {
+	switch d.Type {
+	case DatumHashOption:
+		return cborEnc.Marshal([]interface{}{d.Type, d.Hash})
+	case InlineDatumOption:
+		data, err := d.Data.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		return cborEnc.Marshal([]interface{}{d.Type, cbor.Tag{Number: 24, Content: data}})
+	default:
+		return nil, fmt.Errorf("cbor: invalid datum option type %d", d.Type)
+	}
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (d *datumOption) UnmarshalCBOR(data []byte) error {
+	items := []cbor.RawMessage{}
+	if err := cborDec.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if len(items) != 2 {
+		return fmt.Errorf("cbor: invalid datum option length %d", len(items))
+	}
+	if err := cborDec.Unmarshal(items[0], &d.Type); err != nil {
+		return err
+	}
+
+	switch d.Type {
+	case DatumHashOption:
+		return cborDec.Unmarshal(items[1], &d.Hash)
+	case InlineDatumOption:
+		content, err := unwrapEncodedCBOR(items[1])
+		if err != nil {
+			return err
+		}
+		d.Data = &PlutusData{}
+		return d.Data.UnmarshalCBOR(content)
+	default:
+		return fmt.Errorf("cbor: invalid datum option type %d", d.Type)
+	}
+}
+
 type TxBody struct {
 	Inputs  []*TxInput  `cbor:"0,keyasint"`
 	Outputs []*TxOutput `cbor:"1,keyasint"`
 	Fee     Coin        `cbor:"2,keyasint"`
 
 	// Optionals
-	TTL                   Uint64        `cbor:"3,keyasint,omitempty"`
-	Certificates          []Certificate `cbor:"4,keyasint,omitempty"`
-	Withdrawals           interface{}   `cbor:"5,keyasint,omitempty"` // unsupported
-	Update                interface{}   `cbor:"6,keyasint,omitempty"` // unsupported
-	AuxiliaryDataHash     *Hash32       `cbor:"7,keyasint,omitempty"`
-	ValidityIntervalStart Uint64        `cbor:"8,keyasint,omitempty"`
-	Mint                  *Mint         `cbor:"9,keyasint,omitempty"`
-	ScriptDataHash        *Hash32       `cbor:"10,keyasint,omitempty"`
-	Collateral            []TxInput     `cbor:"11,keyasint,omitempty"`
-	RequiredSigners       []AddrKeyHash `cbor:"12,keyasint,omitempty"`
-	NetworkID             Uint64        `cbor:"13,keyasint,omitempty"`
+	TTL                   Uint64              `cbor:"3,keyasint,omitempty"`
+	Certificates          []Certificate       `cbor:"4,keyasint,omitempty"`
+	Withdrawals           *Withdrawals        `cbor:"5,keyasint,omitempty"`
+	Update                *Update             `cbor:"6,keyasint,omitempty"`
+	AuxiliaryDataHash     *Hash32             `cbor:"7,keyasint,omitempty"`
+	ValidityIntervalStart Uint64              `cbor:"8,keyasint,omitempty"`
+	Mint                  *Mint               `cbor:"9,keyasint,omitempty"`
+	ScriptDataHash        *Hash32             `cbor:"10,keyasint,omitempty"`
+	Collateral            []TxInput           `cbor:"11,keyasint,omitempty"`
+	RequiredSigners       []AddrKeyHash       `cbor:"12,keyasint,omitempty"`
+	NetworkID             Uint64              `cbor:"13,keyasint,omitempty"`
+	CollateralReturn      *TxOutput           `cbor:"16,keyasint,omitempty"`
+	TotalCollateral       Coin                `cbor:"17,keyasint,omitempty"`
+	ReferenceInputs       []TxInput           `cbor:"18,keyasint,omitempty"`
+	VotingProcedures      *VotingProcedures   `cbor:"19,keyasint,omitempty"`
+	ProposalProcedures    []ProposalProcedure `cbor:"20,keyasint,omitempty"`
+	CurrentTreasuryValue  Coin                `cbor:"21,keyasint,omitempty"`
+	Donation              Coin                `cbor:"22,keyasint,omitempty"`
+
+	original originalCBOR
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The original encoding is used for decoded bodies that weren't modified.
+func (body *TxBody) MarshalCBOR() ([]byte, error) {
+	type rawTxBody TxBody
+	bytes, err := cborEnc.Marshal((*rawTxBody)(body))
+	if err != nil {
+		return nil, err
+	}
+	return body.original.bytes(bytes), nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (body *TxBody) UnmarshalCBOR(data []byte) error {
+	type rawTxBody TxBody
+	var rb rawTxBody
+	if err := cborDec.Unmarshal(data, &rb); err != nil {
+		return err
+	}
+	encoded, err := cborEnc.Marshal(&rb)
+	if err != nil {
+		return err
+	}
+	*body = TxBody(rb)
+	body.original = newOriginalCBOR(data, encoded)
+	return nil
 }
 
 // Hash returns the transaction body hash using blake2b256.
@@ -1,8 +1,10 @@
 package cardano
 
 import (
+	"bytes"
 	"fmt"
 	"math"
+	"math/big"
 
 	"github.com/echovl/cardano-go/crypto"
 	"golang.org/x/crypto/blake2b"
@@ -10,11 +12,20 @@ import (
 
 // TxBuilder is a transaction builder.
 type TxBuilder struct {
-	tx       *Tx
-	protocol *ProtocolParams
-	pkeys    []crypto.PrvKey
+	tx        *Tx
+	protocol  *ProtocolParams
+	pkeys     []crypto.PrvKey
+	xkeys     []crypto.XPrvKey
+	inputKeys []crypto.PrvKey
 
-	changeReceiver *Address
+	changeReceiver     *Address
+	collateralReceiver *Address
+
+	coinSelection   CoinSelection
+	availableInputs []*TxInput
+
+	expectedSigners          []AddrKeyHash
+	expectedBootstrapSigners []Address
 }
 
 // NewTxBuilder returns a new instance of TxBuilder.
@@ -22,6 +33,7 @@ func NewTxBuilder(protocol *ProtocolParams) *TxBuilder {
 	return &TxBuilder{
 		protocol: protocol,
 		pkeys:    []crypto.PrvKey{},
+		xkeys:    []crypto.XPrvKey{},
 		tx: &Tx{
 			IsValid: true,
 		},
@@ -33,6 +45,20 @@ func (tb *TxBuilder) AddInputs(inputs ...*TxInput) {
 	tb.tx.Body.Inputs = append(tb.tx.Body.Inputs, inputs...)
 }
 
+// AddReferenceInputs adds reference inputs to the transaction.
+func (tb *TxBuilder) AddReferenceInputs(inputs ...*TxInput) {
+	for _, input := range inputs {
+		tb.tx.Body.ReferenceInputs = append(tb.tx.Body.ReferenceInputs, *input)
+	}
+}
+
+// AddCollateralInputs adds collateral inputs to the transaction.
+func (tb *TxBuilder) AddCollateralInputs(inputs ...*TxInput) {
+	for _, input := range inputs {
+		tb.tx.Body.Collateral = append(tb.tx.Body.Collateral, *input)
+	}
+}
+
 // AddOutputs adds outputs to the transaction.
 func (tb *TxBuilder) AddOutputs(outputs ...*TxOutput) {
 	tb.tx.Body.Outputs = append(tb.tx.Body.Outputs, outputs...)
@@ -58,22 +84,154 @@ func (tb *TxBuilder) AddCertificate(cert Certificate) {
 	tb.tx.Body.Certificates = append(tb.tx.Body.Certificates, cert)
 }
 
+// AddWithdrawal adds a reward withdrawal to the transaction.
+func (tb *TxBuilder) AddWithdrawal(rewardAddr Address, amount Coin) {
+	if tb.tx.Body.Withdrawals == nil {
+		tb.tx.Body.Withdrawals = NewWithdrawals()
+	}
+	tb.tx.Body.Withdrawals.Set(rewardAddr, amount)
+}
+
+// AddVote adds a vote of the voter for a governance action.
+func (tb *TxBuilder) AddVote(voter Voter, id GovActionID, procedure VotingProcedure) {
+	if tb.tx.Body.VotingProcedures == nil {
+		tb.tx.Body.VotingProcedures = &VotingProcedures{}
+	}
+	tb.tx.Body.VotingProcedures.Add(voter, id, procedure)
+}
+
+// AddProposal adds a governance action proposal to the transaction.
+func (tb *TxBuilder) AddProposal(proposal ProposalProcedure) {
+	tb.tx.Body.ProposalProcedures = append(tb.tx.Body.ProposalProcedures, proposal)
+}
+
+// SetDonation sets the amount donated to the treasury.
+func (tb *TxBuilder) SetDonation(donation Coin) {
+	tb.tx.Body.Donation = donation
+}
+
+// SetCurrentTreasuryValue sets the current treasury value asserted by the transaction.
+func (tb *TxBuilder) SetCurrentTreasuryValue(value Coin) {
+	tb.tx.Body.CurrentTreasuryValue = value
+}
+
 // AddNativeScript adds a native script to the transaction.
 func (tb *TxBuilder) AddNativeScript(script NativeScript) {
 	tb.tx.WitnessSet.Scripts = append(tb.tx.WitnessSet.Scripts, script)
 }
 
+// AddPlutusV1Script adds a Plutus V1 script to the transaction.
+func (tb *TxBuilder) AddPlutusV1Script(script PlutusV1Script) {
+	tb.tx.WitnessSet.PlutusV1Scripts = append(tb.tx.WitnessSet.PlutusV1Scripts, script)
+}
+
+// AddPlutusV2Script adds a Plutus V2 script to the transaction.
+func (tb *TxBuilder) AddPlutusV2Script(script PlutusV2Script) {
+	tb.tx.WitnessSet.PlutusV2Scripts = append(tb.tx.WitnessSet.PlutusV2Scripts, script)
+}
+
+// AddPlutusV3Script adds a Plutus V3 script to the transaction.
+func (tb *TxBuilder) AddPlutusV3Script(script PlutusV3Script) {
+	tb.tx.WitnessSet.PlutusV3Scripts = append(tb.tx.WitnessSet.PlutusV3Scripts, script)
+}
+
+// AddRedeemer adds a redeemer to the transaction.
+func (tb *TxBuilder) AddRedeemer(redeemer Redeemer) {
+	tb.tx.WitnessSet.Redeemers = append(tb.tx.WitnessSet.Redeemers, redeemer)
+}
+
+// AddDatum adds a datum to the transaction witness set.
+func (tb *TxBuilder) AddDatum(datum PlutusData) {
+	tb.tx.WitnessSet.PlutusData = append(tb.tx.WitnessSet.PlutusData, datum)
+}
+
+// AddRequiredSigners adds key hashes that must sign the transaction, they are
+// visible to Plutus scripts.
+func (tb *TxBuilder) AddRequiredSigners(signers ...AddrKeyHash) {
+	tb.tx.Body.RequiredSigners = append(tb.tx.Body.RequiredSigners, signers...)
+}
+
+// AddExpectedSigners declares key hashes that will sign the transaction later,
+// outside of the builder. Their witnesses are accounted for in the fee.
+func (tb *TxBuilder) AddExpectedSigners(signers ...AddrKeyHash) {
+	tb.expectedSigners = append(tb.expectedSigners, signers...)
+}
+
+// AddExpectedBootstrapSigners declares Byron addresses whose keys will sign the
+// transaction later, outside of the builder. Their bootstrap witnesses are accounted
+// for in the fee. Byron inputs with TxInput.Address set are detected automatically.
+func (tb *TxBuilder) AddExpectedBootstrapSigners(addrs ...Address) {
+	tb.expectedBootstrapSigners = append(tb.expectedBootstrapSigners, addrs...)
+}
+
 // Mint adds a new multiasset to mint.
 func (tb *TxBuilder) Mint(asset *Mint) {
 	tb.tx.Body.Mint = asset
 }
 
+// MintReferenceToken mints a CIP-68 reference NFT and its user tokens, and adds the
+// outputs sending the reference NFT with the inline metadata datum and the user tokens.
+// If the datum version is not set, version 1 is used, or version 3 for RFTs.
+func (tb *TxBuilder) MintReferenceToken(token ReferenceToken) error {
+	if err := token.validate(); err != nil {
+		return err
+	}
+	if token.Datum.Version == 0 {
+		token.Datum.Version = 1
+		if token.Label == RFTTokenLabel {
+			token.Datum.Version = 3
+		}
+	}
+
+	if tb.tx.Body.Mint == nil {
+		tb.tx.Body.Mint = NewMint()
+	}
+	mintAssets := tb.tx.Body.Mint.Get(token.PolicyID)
+	if mintAssets == nil {
+		mintAssets = NewMintAssets()
+		tb.tx.Body.Mint.Set(token.PolicyID, mintAssets)
+	}
+	mintAssets.Set(token.ReferenceAssetName(), big.NewInt(1))
+	mintAssets.Set(token.UserAssetName(), new(big.Int).SetUint64(token.Quantity))
+
+	datum := token.Datum.PlutusData()
+	refOutput := NewTxOutput(token.ReferenceAddress, NewValueWithAssets(0,
+		NewMultiAsset().Set(token.PolicyID, NewAssets().Set(token.ReferenceAssetName(), 1)),
+	))
+	refOutput.Datum = &datum
+	refOutput.Amount.Coin = tb.MinCoinsForTxOut(refOutput)
+
+	userOutput := NewTxOutput(token.UserAddress, NewValueWithAssets(0,
+		NewMultiAsset().Set(token.PolicyID, NewAssets().Set(token.UserAssetName(), BigNum(token.Quantity))),
+	))
+	userOutput.Amount.Coin = tb.MinCoinsForTxOut(userOutput)
+
+	tb.AddOutputs(refOutput, userOutput)
+
+	return nil
+}
+
+// SelectInputs instructs the builder to select the inputs required to cover the outputs,
+// the fee and the change output from the available inputs, using the given coin selection.
+// The inputs added with AddInputs are always spent. A change address must be set with
+// AddChangeIfNeeded.
+func (tb *TxBuilder) SelectInputs(selection CoinSelection, available ...*TxInput) {
+	tb.coinSelection = selection
+	tb.availableInputs = available
+}
+
 // AddChangeIfNeeded instructs the builder to calculate the required fee for the
 // transaction and to add an aditional output for the change if there is any.
 func (tb *TxBuilder) AddChangeIfNeeded(changeAddr Address) {
 	tb.changeReceiver = &changeAddr
 }
 
+// AddCollateralReturnIfNeeded instructs the builder to calculate the total collateral
+// required for the transaction and to add a collateral return output if there is any excess.
+func (tb *TxBuilder) AddCollateralReturnIfNeeded(returnAddr Address) {
+	tb.collateralReceiver = &returnAddr
+}
+
 func (tb *TxBuilder) calculateAmounts() (*Value, *Value) {
 	input, output := NewValue(0), NewValue(tb.totalDeposits())
 	for _, in := range tb.tx.Body.Inputs {
@@ -85,24 +243,44 @@ func (tb *TxBuilder) calculateAmounts() (*Value, *Value) {
 	if tb.tx.Body.Mint != nil {
 		input = input.Add(NewValueWithAssets(0, tb.tx.Body.Mint.MultiAsset()))
 	}
+	if tb.tx.Body.Withdrawals != nil {
+		input = input.Add(NewValue(tb.tx.Body.Withdrawals.Total()))
+	}
+	input = input.Add(NewValue(tb.totalRefunds()))
 	return input, output
 }
 
 func (tb *TxBuilder) totalDeposits() Coin {
-	certs := tb.tx.Body.Certificates
 	var deposit Coin
-	if len(certs) != 0 {
-		for _, cert := range certs {
-			if cert.Type == StakeRegistration {
-				deposit += tb.protocol.KeyDeposit
-			}
+	for _, cert := range tb.tx.Body.Certificates {
+		switch cert.Type {
+		case StakeRegistration:
+			deposit += tb.protocol.KeyDeposit
+		case Registration, StakeRegistrationDelegation, VoteRegistrationDelegation,
+			StakeVoteRegistrationDelegation, DRepRegistration:
+			deposit += cert.Deposit
 		}
 	}
-	return deposit
+	for _, proposal := range tb.tx.Body.ProposalProcedures {
+		deposit += proposal.Deposit
+	}
+	return deposit + tb.tx.Body.Donation
+}
+
+func (tb *TxBuilder) totalRefunds() Coin {
+	var refund Coin
+	for _, cert := range tb.tx.Body.Certificates {
+		switch cert.Type {
+		case Unregistration, DRepDeregistration:
+			refund += cert.Deposit
+		}
+	}
+	return refund
 }
 
 // MinFee computes the minimal fee required for the transaction.
-// This assumes that the inputs-outputs are defined and signing keys are present.
+// This assumes that the inputs-outputs are defined. The witnesses of the signers
+// without a signing key in the builder are estimated using dummy keys and signatures.
 func (tb *TxBuilder) MinFee() (Coin, error) {
 	// Set a temporary realistic fee in order to serialize a valid transaction
 	currentFee := tb.tx.Body.Fee
@@ -116,9 +294,19 @@ func (tb *TxBuilder) MinFee() (Coin, error) {
 }
 
 // MinCoinsForTxOut computes the minimal amount of coins required for a given transaction output.
+// The Babbage formula is used when the protocol version is Babbage or later, or when the
+// protocol version is not set and CoinsPerUTxOByte is. Otherwise the Alonzo formula is used.
+func (tb *TxBuilder) MinCoinsForTxOut(txOut *TxOutput) Coin {
+	if tb.protocol.IsBabbage() {
+		return tb.babbageMinCoinsForTxOut(txOut)
+	}
+	return tb.alonzoMinCoinsForTxOut(txOut)
+}
+
+// alonzoMinCoinsForTxOut computes the minimal amount of coins of an output in Alonzo.
 // More info could be found in
 // <https://github.com/input-output-hk/cardano-ledger/blob/master/doc/explanations/min-utxo-alonzo.rst>
-func (tb *TxBuilder) MinCoinsForTxOut(txOut *TxOutput) Coin {
+func (tb *TxBuilder) alonzoMinCoinsForTxOut(txOut *TxOutput) Coin {
 	var size uint
 	if txOut.Amount.OnlyCoin() {
 		size = 1
@@ -131,19 +319,137 @@ func (tb *TxBuilder) MinCoinsForTxOut(txOut *TxOutput) Coin {
 			float64(numAssets*12+assetsLength+numPIDs*28+7)/8,
 		))
 	}
+	if txOut.DatumHash != nil {
+		size += dataHashSize
+	}
 	return Coin(utxoEntrySizeWithoutVal+size) * tb.protocol.CoinsPerUTXOWord
 }
 
-// Calculate minimum lovelace a transaction output needs to hold post alonzo.
-// This implementation is copied from the origianl Haskell implementation:
-// https://github.com/input-output-hk/cardano-ledger/blob/eb053066c1d3bb51fb05978eeeab88afc0b049b2/eras/babbage/impl/src/Cardano/Ledger/Babbage/Rules/Utxo.hs#L242-L265
-// TODO:
+// babbageMinCoinsForTxOut computes the minimal amount of coins of an output post Alonzo:
+// (160 + serialized output size) * coinsPerUTxOByte, datums and reference scripts included.
+// The coins of the output change its size, so the output is serialized holding the
+// resulting amount until it doesn't change.
+// More info could be found in
+// <https://github.com/input-output-hk/cardano-ledger/blob/eb053066c1d3bb51fb05978eeeab88afc0b049b2/eras/babbage/impl/src/Cardano/Ledger/Babbage/Rules/Utxo.hs#L242-L265>
+func (tb *TxBuilder) babbageMinCoinsForTxOut(txOut *TxOutput) Coin {
+	out := *txOut
+	var minCoins Coin
+	for {
+		out.Amount = &Value{Coin: minCoins, MultiAsset: txOut.Amount.MultiAsset}
+		outBytes, err := out.MarshalCBOR()
+		if err != nil {
+			return minCoins
+		}
+		coins := Coin(utxoEntrySizeBabbage+len(outBytes)) * tb.protocol.CoinsPerUTxOByte
+		if coins <= minCoins {
+			return minCoins
+		}
+		minCoins = coins
+	}
+}
 
 // calculateMinFee computes the minimal fee required for the transaction.
 func (tb *TxBuilder) calculateMinFee() Coin {
+	txLength := uint64(tb.estimatedSize())
+	return tb.protocol.MinFeeA*Coin(txLength) + tb.protocol.MinFeeB + tb.scriptsFee() + tb.refScriptsFee()
+}
+
+// estimatedSize computes the size of the transaction once it's signed by all the signers.
+func (tb *TxBuilder) estimatedSize() int {
+	// Add dummy witnesses for the signers that will sign later
+	witnessSet := tb.tx.WitnessSet
+	tb.tx.WitnessSet.VKeyWitnessSet = append([]VKeyWitness{}, witnessSet.VKeyWitnessSet...)
+	for range tb.missingSigners() {
+		tb.tx.WitnessSet.VKeyWitnessSet = append(tb.tx.WitnessSet.VKeyWitnessSet, VKeyWitness{
+			VKey:      make(crypto.PubKey, 32),
+			Signature: make([]byte, 64),
+		})
+	}
+	tb.tx.WitnessSet.BootstrapWitnesses = append([]BootstrapWitness{}, witnessSet.BootstrapWitnesses...)
+	for _, addr := range tb.missingBootstrapSigners() {
+		attrs, _ := addr.Byron.attributesBytes()
+		tb.tx.WitnessSet.BootstrapWitnesses = append(tb.tx.WitnessSet.BootstrapWitnesses, BootstrapWitness{
+			VKey:       make(crypto.PubKey, 32),
+			Signature:  make([]byte, 64),
+			ChainCode:  make([]byte, 32),
+			Attributes: attrs,
+		})
+	}
 	txBytes := tb.tx.Bytes()
-	txLength := uint64(len(txBytes))
-	return tb.protocol.MinFeeA*Coin(txLength) + tb.protocol.MinFeeB
+	tb.tx.WitnessSet = witnessSet
+
+	return len(txBytes)
+}
+
+// scriptsFee computes the fee for the execution units of the redeemers.
+func (tb *TxBuilder) scriptsFee() Coin {
+	var mem, steps uint64
+	for _, redeemer := range tb.tx.WitnessSet.Redeemers {
+		mem += redeemer.ExUnits.Mem
+		steps += redeemer.ExUnits.Steps
+	}
+	prices := tb.protocol.ExecutionCosts
+	fee := new(big.Rat).Mul(new(big.Rat).SetUint64(mem), prices.Mem.rat())
+	fee.Add(fee, new(big.Rat).Mul(new(big.Rat).SetUint64(steps), prices.Steps.rat()))
+	return ceilRat(fee)
+}
+
+const (
+	refScriptsFeeTierSize        = 25600
+	refScriptsFeeTierMultiplierP = 12
+	refScriptsFeeTierMultiplierQ = 10
+)
+
+// refScriptsFee computes the Conway fee for the reference scripts of the inputs and
+// reference inputs. The price per byte is multiplied by 1.2 every 25 KiB.
+// More info could be found in
+// <https://github.com/IntersectMBO/cardano-ledger/blob/master/docs/adr/2024-08-14_009-refscripts-fee-change.md>
+func (tb *TxBuilder) refScriptsFee() Coin {
+	if tb.protocol.MinFeeRefScriptCostPerByte.P == 0 {
+		return 0
+	}
+
+	var size uint64
+	inputs := []TxInput{}
+	for _, input := range append(tb.inputs(), tb.tx.Body.ReferenceInputs...) {
+		if input.ScriptRef == nil || containsTxInput(inputs, input) {
+			continue
+		}
+		inputs = append(inputs, input)
+		size += input.ScriptRef.size()
+	}
+
+	fee := new(big.Rat)
+	price := tb.protocol.MinFeeRefScriptCostPerByte.rat()
+	multiplier := big.NewRat(refScriptsFeeTierMultiplierP, refScriptsFeeTierMultiplierQ)
+	for size > 0 {
+		tierSize := uint64(refScriptsFeeTierSize)
+		if size < tierSize {
+			tierSize = size
+		}
+		fee.Add(fee, new(big.Rat).Mul(new(big.Rat).SetUint64(tierSize), price))
+		price = new(big.Rat).Mul(price, multiplier)
+		size -= tierSize
+	}
+	return Coin(new(big.Int).Quo(fee.Num(), fee.Denom()).Uint64())
+}
+
+func containsTxInput(inputs []TxInput, input TxInput) bool {
+	for _, in := range inputs {
+		if in.TxHash.String() == input.TxHash.String() && in.Index == input.Index {
+			return true
+		}
+	}
+	return false
+}
+
+// ceilRat returns the smallest Coin greater than or equal to r.
+func ceilRat(r *big.Rat) Coin {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return Coin(q.Uint64())
 }
 
 // Sign adds signing keys to create signatures for the witness set.
@@ -151,15 +457,46 @@ func (tb *TxBuilder) Sign(privateKeys ...crypto.PrvKey) {
 	tb.pkeys = append(tb.pkeys, privateKeys...)
 }
 
+// SignInputs adds signing keys that are only used when the transaction spends an
+// input or collateral input locked by their payment key. The input address must be
+// set in TxInput.Address. It's useful to sign the inputs chosen by SelectInputs.
+func (tb *TxBuilder) SignInputs(privateKeys ...crypto.PrvKey) {
+	tb.inputKeys = append(tb.inputKeys, privateKeys...)
+}
+
+// SignWithXPrvKeys adds extended signing keys to create signatures for the witness set.
+// A bootstrap witness is created for the keys that correspond to a Byron input, the
+// input address must be set in TxInput.Address. Other keys create vkey witnesses.
+func (tb *TxBuilder) SignWithXPrvKeys(xprvKeys ...crypto.XPrvKey) {
+	tb.xkeys = append(tb.xkeys, xprvKeys...)
+}
+
 // Reset resets the builder to its initial state.
 func (tb *TxBuilder) Reset() {
 	tb.tx = &Tx{IsValid: true}
 	tb.pkeys = []crypto.PrvKey{}
+	tb.xkeys = []crypto.XPrvKey{}
+	tb.inputKeys = nil
 	tb.changeReceiver = nil
+	tb.collateralReceiver = nil
+	tb.coinSelection = nil
+	tb.availableInputs = nil
+	tb.expectedSigners = nil
+	tb.expectedBootstrapSigners = nil
 }
 
 // Build returns a new transaction using the inputs, outputs and keys provided.
 func (tb *TxBuilder) Build() (*Tx, error) {
+	if err := tb.setNativeScriptsValidityInterval(); err != nil {
+		return nil, err
+	}
+
+	if tb.coinSelection != nil {
+		if err := tb.selectInputs(); err != nil {
+			return nil, err
+		}
+	}
+
 	inputAmount, outputAmount := tb.calculateAmounts()
 
 	// Check input-output value conservation
@@ -191,14 +528,185 @@ func (tb *TxBuilder) Build() (*Tx, error) {
 		return nil, err
 	}
 
+	if err := tb.checkLimits(); err != nil {
+		return nil, err
+	}
+
 	return tb.tx, nil
 }
 
+// splitChange returns the change outputs. The change is split into several outputs
+// when its value exceeds the maximum value size, the first output holds all the coins.
+func (tb *TxBuilder) splitChange(change *Value) []*TxOutput {
+	maxValueSize := tb.protocol.MaxValueSize
+	if maxValueSize == 0 || valueSize(change) <= maxValueSize {
+		return []*TxOutput{NewTxOutput(*tb.changeReceiver, change)}
+	}
+
+	// Fill each bundle with as many assets as possible, the coins of the
+	// bundle are set to the maximum to account for their size
+	bundle := NewMultiAsset()
+	bundles := []*MultiAsset{bundle}
+	for _, asset := range selectionAssets(NewValueWithAssets(0, change.MultiAsset)) {
+		quantity := BigNum(asset.quantity(change))
+		bundle.add(asset.policyID, asset.name, quantity)
+		if bundle.numAssets() > 1 && valueSize(NewValueWithAssets(math.MaxUint64, bundle)) > maxValueSize {
+			bundle.remove(asset.policyID, asset.name)
+			bundle = NewMultiAsset()
+			bundle.add(asset.policyID, asset.name, quantity)
+			bundles = append(bundles, bundle)
+		}
+	}
+
+	outputs := make([]*TxOutput, len(bundles))
+	for i, bundle := range bundles {
+		outputs[i] = NewTxOutput(*tb.changeReceiver, NewValueWithAssets(0, bundle))
+	}
+	outputs[0].Amount.Coin = change.Coin
+	return outputs
+}
+
+// checkLimits validates the transaction size and the value size of the outputs.
+func (tb *TxBuilder) checkLimits() error {
+	if maxValueSize := tb.protocol.MaxValueSize; maxValueSize > 0 {
+		for i, output := range tb.tx.Body.Outputs {
+			if size := valueSize(output.Amount); size > maxValueSize {
+				return fmt.Errorf(
+					"value size of output %d exceeds the maximum, got %d bytes want at most %d bytes",
+					i,
+					size,
+					maxValueSize,
+				)
+			}
+		}
+	}
+	if maxTxSize := tb.protocol.MaxTxSize; maxTxSize > 0 {
+		if size := uint(tb.estimatedSize()); size > maxTxSize {
+			return fmt.Errorf(
+				"transaction size exceeds the maximum, got %d bytes want at most %d bytes",
+				size,
+				maxTxSize,
+			)
+		}
+	}
+	return nil
+}
+
+// valueSize returns the serialized size of the value.
+func valueSize(v *Value) uint {
+	valueBytes, err := cborEnc.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return uint(len(valueBytes))
+}
+
+// maxSelectionRounds is the maximum number of coin selections done to cover the fee and change.
+const maxSelectionRounds = 10
+
+// selectInputs adds the inputs selected by the coin selection. The selection is repeated
+// until the selected inputs also cover the fee and the minimum coins of the change output.
+func (tb *TxBuilder) selectInputs() error {
+	if tb.changeReceiver == nil {
+		return fmt.Errorf("automatic input selection requires a change address")
+	}
+
+	fixedInputs := tb.tx.Body.Inputs
+	available := []*TxInput{}
+	for _, input := range tb.availableInputs {
+		if !containsInput(fixedInputs, input) {
+			available = append(available, input)
+		}
+	}
+
+	// Inputs covering the fee whose coin-only change is below the minimum, the
+	// change is burned if no other inputs can be selected
+	var dustInputs []*TxInput
+	var extra Coin
+	for i := 0; i < maxSelectionRounds; i++ {
+		tb.tx.Body.Inputs = fixedInputs
+		inputAmount, outputAmount := tb.calculateAmounts()
+		target := outputAmount.Add(NewValue(extra)).Sub(inputAmount)
+
+		selected, err := tb.coinSelection.Select(available, target)
+		if err != nil {
+			if dustInputs != nil {
+				tb.tx.Body.Inputs = dustInputs
+				return nil
+			}
+			return err
+		}
+		tb.tx.Body.Inputs = append(append([]*TxInput{}, fixedInputs...), selected...)
+
+		shortfall, dust, err := tb.selectionShortfall()
+		if err != nil {
+			return err
+		}
+		if shortfall == 0 {
+			return nil
+		}
+		if dust {
+			dustInputs = tb.tx.Body.Inputs
+		}
+		extra += shortfall
+	}
+
+	if dustInputs != nil {
+		tb.tx.Body.Inputs = dustInputs
+		return nil
+	}
+	tb.tx.Body.Inputs = fixedInputs
+	return fmt.Errorf("coin selection didn't cover the fee and change after %d rounds", maxSelectionRounds)
+}
+
+// selectionShortfall returns the coins missing in the inputs to pay the fee and
+// the minimum coins of the change outputs. It also reports whether the inputs pay
+// the fee and only a coin-only change below its minimum coins is missing.
+func (tb *TxBuilder) selectionShortfall() (Coin, bool, error) {
+	inputAmount, outputAmount := tb.calculateAmounts()
+	changeAmount := inputAmount.Sub(outputAmount)
+	changeOutputs := tb.splitChange(changeAmount)
+
+	outputs := tb.tx.Body.Outputs
+	tb.tx.Body.Outputs = append(changeOutputs, outputs...)
+	minFee, err := tb.MinFee()
+	tb.tx.Body.Outputs = outputs
+	if err != nil {
+		return 0, false, err
+	}
+
+	var minCoins Coin
+	for _, output := range changeOutputs {
+		minCoins += tb.MinCoinsForTxOut(output)
+	}
+	required := outputAmount.Coin + minFee
+	if NewValueWithAssets(0, changeAmount.MultiAsset).IsZero() {
+		// A coin-only change below its minimum coins would be burned
+		if inputAmount.Coin > required && inputAmount.Coin < required+minCoins {
+			return required + minCoins - inputAmount.Coin, true, nil
+		}
+	} else {
+		required += minCoins
+	}
+	if inputAmount.Coin >= required {
+		return 0, false, nil
+	}
+	return required - inputAmount.Coin, false, nil
+}
+
+func containsInput(inputs []*TxInput, input *TxInput) bool {
+	for _, in := range inputs {
+		if in.TxHash.String() == input.TxHash.String() && in.Index == input.Index {
+			return true
+		}
+	}
+	return false
+}
+
 func (tb *TxBuilder) addChangeIfNeeded(inputAmount, outputAmount *Value) error {
-	// Temporary fee to serialize a valid transaction
+	// Temporary fee to serialize a valid transaction, the missing witnesses
+	// are estimated by calculateMinFee
 	tb.tx.Body.Fee = 2e5
-
-	// TODO: We should build a fake tx with hardcoded data like signatures, hashes, etc
 	if err := tb.build(); err != nil {
 		return err
 	}
@@ -217,12 +725,16 @@ func (tb *TxBuilder) addChangeIfNeeded(inputAmount, outputAmount *Value) error {
 		return nil
 	}
 
-	// Construct change output
+	// Construct change outputs, the first one holds the remaining coins
 	changeAmount := inputAmount.Sub(outputAmount)
-	changeOutput := NewTxOutput(*tb.changeReceiver, changeAmount)
+	changeOutputs := tb.splitChange(changeAmount)
+	changeOutput := changeOutputs[0]
 
-	changeMinCoins := tb.MinCoinsForTxOut(changeOutput)
-	if changeAmount.Coin < changeMinCoins {
+	var totalMinCoins Coin
+	for _, output := range changeOutputs {
+		totalMinCoins += tb.MinCoinsForTxOut(output)
+	}
+	if changeAmount.Coin < totalMinCoins {
 		if changeAmount.OnlyCoin() {
 			tb.tx.Body.Fee = minFee + changeAmount.Coin // burn change
 			return nil
@@ -230,18 +742,23 @@ func (tb *TxBuilder) addChangeIfNeeded(inputAmount, outputAmount *Value) error {
 		return fmt.Errorf(
 			"insuficient input for change output with multiassets, got %v want %v",
 			inputAmount.Coin,
-			inputAmount.Coin+changeMinCoins-changeAmount.Coin,
+			inputAmount.Coin+totalMinCoins-changeAmount.Coin,
 		)
 	}
+	for _, output := range changeOutputs[1:] {
+		output.Amount.Coin = tb.MinCoinsForTxOut(output)
+		changeOutput.Amount.Coin -= output.Amount.Coin
+	}
+	changeMinCoins := tb.MinCoinsForTxOut(changeOutput)
 
-	tb.tx.Body.Outputs = append([]*TxOutput{changeOutput}, tb.tx.Body.Outputs...)
+	tb.tx.Body.Outputs = append(changeOutputs, tb.tx.Body.Outputs...)
 
 	newMinFee := tb.calculateMinFee()
-	changeAmount.Coin = changeAmount.Coin + minFee - newMinFee
-	if changeAmount.Coin < changeMinCoins {
+	changeOutput.Amount.Coin = changeOutput.Amount.Coin + minFee - newMinFee
+	if changeOutput.Amount.Coin < changeMinCoins {
 		if changeAmount.OnlyCoin() {
-			tb.tx.Body.Fee = newMinFee + changeAmount.Coin // burn change
-			tb.tx.Body.Outputs = tb.tx.Body.Outputs[1:]    // remove change output
+			tb.tx.Body.Fee = newMinFee + changeOutput.Amount.Coin // burn change
+			tb.tx.Body.Outputs = tb.tx.Body.Outputs[1:]           // remove change output
 			return nil
 		}
 		return fmt.Errorf(
@@ -267,14 +784,238 @@ func (tb *TxBuilder) build() error {
 	}
 
 	// Create witness set
-	tb.tx.WitnessSet.VKeyWitnessSet = make([]VKeyWitness, len(tb.pkeys))
-	for i, pkey := range tb.pkeys {
+	pkeys := tb.signingKeys()
+	tb.tx.WitnessSet.VKeyWitnessSet = make([]VKeyWitness, len(pkeys))
+	for i, pkey := range pkeys {
 		tb.tx.WitnessSet.VKeyWitnessSet[i] = VKeyWitness{
 			VKey:      pkey.PubKey(),
 			Signature: pkey.Sign(txHash),
 		}
 	}
+	tb.tx.WitnessSet.BootstrapWitnesses = nil
+	for _, xkey := range tb.xkeys {
+		addr := tb.bootstrapAddress(xkey.XPubKey())
+		if addr == nil {
+			tb.tx.WitnessSet.VKeyWitnessSet = append(tb.tx.WitnessSet.VKeyWitnessSet, VKeyWitness{
+				VKey:      xkey.PubKey(),
+				Signature: xkey.Sign(txHash),
+			})
+			continue
+		}
+		attrs, err := addr.Byron.attributesBytes()
+		if err != nil {
+			return err
+		}
+		tb.tx.WitnessSet.BootstrapWitnesses = append(tb.tx.WitnessSet.BootstrapWitnesses, BootstrapWitness{
+			VKey:       xkey.PubKey(),
+			Signature:  xkey.Sign(txHash),
+			ChainCode:  xkey[64:],
+			Attributes: attrs,
+		})
+	}
+
+	return nil
+}
+
+// signingKeys returns the keys added with Sign and the keys added with SignInputs
+// that lock an input or collateral input of the transaction.
+func (tb *TxBuilder) signingKeys() []crypto.PrvKey {
+	pkeys := append([]crypto.PrvKey{}, tb.pkeys...)
+	inputs := append(tb.inputs(), tb.tx.Body.Collateral...)
+	for _, pkey := range tb.inputKeys {
+		keyHash, err := pkey.PubKey().Hash()
+		if err != nil {
+			continue
+		}
+		for _, input := range inputs {
+			addr := input.Address
+			if addr == nil || addr.Type == Byron || addr.Type == Reward || addr.Payment.Type != KeyCredential {
+				continue
+			}
+			if bytes.Equal(addr.Payment.KeyHash, keyHash) {
+				pkeys = append(pkeys, pkey)
+				break
+			}
+		}
+	}
+	return pkeys
+}
+
+// signerKeyHashes returns the key hashes of the vkey witnesses created by the builder.
+func (tb *TxBuilder) signerKeyHashes() []AddrKeyHash {
+	signers := []AddrKeyHash{}
+	for _, pkey := range tb.signingKeys() {
+		if keyHash, err := pkey.PubKey().Hash(); err == nil {
+			signers = append(signers, keyHash)
+		}
+	}
+	for _, xkey := range tb.xkeys {
+		if tb.bootstrapAddress(xkey.XPubKey()) != nil {
+			continue
+		}
+		if keyHash, err := xkey.PubKey().Hash(); err == nil {
+			signers = append(signers, keyHash)
+		}
+	}
+	return signers
+}
+
+// knownSigners returns the key hashes that will sign the transaction: the signers of
+// the builder, the required signers and the expected signers.
+func (tb *TxBuilder) knownSigners() []AddrKeyHash {
+	signers := tb.signerKeyHashes()
+	declared := append(append([]AddrKeyHash{}, tb.tx.Body.RequiredSigners...), tb.expectedSigners...)
+	for _, keyHash := range declared {
+		if !containsKeyHash(signers, keyHash) {
+			signers = append(signers, keyHash)
+		}
+	}
+	return signers
+}
+
+// nativeScriptsRequirements returns the signers and validity interval required by the
+// native scripts of the transaction. Scripts already satisfied by the known signers
+// within the validity interval of the transaction are skipped. For the others the interval
+// of the transaction and then the known signers are preferred, when they don't satisfy
+// a script other keys are assumed to sign the transaction later.
+func (tb *TxBuilder) nativeScriptsRequirements() (scriptRequirements, error) {
+	available := tb.knownSigners()
+	body := &tb.tx.Body
+	interval := scriptRequirements{validityStart: body.ValidityIntervalStart, ttl: body.TTL}
+	var req scriptRequirements
+	for _, script := range tb.tx.WitnessSet.Scripts {
+		if script.Evaluate(available, body.ValidityIntervalStart, body.TTL) {
+			continue
+		}
+		scriptReq, ok := script.requirements(available, interval)
+		if !ok || scriptReq.narrows(interval) {
+			if otherReq, otherOk := script.requirements(nil, interval); otherOk && (!ok || !otherReq.narrows(interval)) {
+				scriptReq, ok = otherReq, otherOk
+			}
+		}
+		if !ok || !req.merge(scriptReq) {
+			return scriptRequirements{}, fmt.Errorf("native scripts can't be satisfied")
+		}
+	}
+	return req, nil
+}
+
+// setNativeScriptsValidityInterval narrows the validity interval of the transaction
+// to the interval required by the native scripts.
+func (tb *TxBuilder) setNativeScriptsValidityInterval() error {
+	req, err := tb.nativeScriptsRequirements()
+	if err != nil {
+		return err
+	}
+	body := &tb.tx.Body
+	if req.validityStart != nil && (body.ValidityIntervalStart == nil || *req.validityStart > *body.ValidityIntervalStart) {
+		body.ValidityIntervalStart = NewUint64(*req.validityStart)
+	}
+	if req.ttl != nil && (body.TTL == nil || *req.ttl < *body.TTL) {
+		body.TTL = NewUint64(*req.ttl)
+	}
+	if body.ValidityIntervalStart != nil && body.TTL != nil && *body.ValidityIntervalStart >= *body.TTL {
+		return fmt.Errorf(
+			"invalid validity interval for native scripts, validity start %d must be lower than ttl %d",
+			*body.ValidityIntervalStart,
+			*body.TTL,
+		)
+	}
+	return nil
+}
+
+// missingScriptSigners returns the native script signers without a signing key in the builder.
+func (tb *TxBuilder) missingScriptSigners() []AddrKeyHash {
+	req, err := tb.nativeScriptsRequirements()
+	if err != nil {
+		return nil
+	}
+	available := tb.signerKeyHashes()
+	missing := []AddrKeyHash{}
+	for _, signer := range req.signers {
+		if !containsKeyHash(available, signer) {
+			missing = append(missing, signer)
+		}
+	}
+	return missing
+}
+
+// missingSigners returns the key hashes that must sign the transaction without a
+// signing key in the builder: native script signers, required signers, expected
+// signers and the payment keys of the inputs and collateral inputs.
+func (tb *TxBuilder) missingSigners() []AddrKeyHash {
+	available := tb.signerKeyHashes()
+	missing := tb.missingScriptSigners()
+	addMissing := func(keyHash AddrKeyHash) {
+		if !containsKeyHash(available, keyHash) && !containsKeyHash(missing, keyHash) {
+			missing = append(missing, keyHash)
+		}
+	}
+	for _, keyHash := range tb.tx.Body.RequiredSigners {
+		addMissing(keyHash)
+	}
+	for _, keyHash := range tb.expectedSigners {
+		addMissing(keyHash)
+	}
+	inputs := append(tb.inputs(), tb.tx.Body.Collateral...)
+	for _, input := range inputs {
+		addr := input.Address
+		if addr == nil || addr.Type == Byron || addr.Type == Reward || addr.Payment.Type != KeyCredential {
+			continue
+		}
+		addMissing(addr.Payment.KeyHash)
+	}
+	return missing
+}
+
+// missingBootstrapSigners returns the Byron addresses of the inputs and the expected
+// bootstrap signers without a signing key in the builder.
+func (tb *TxBuilder) missingBootstrapSigners() []Address {
+	addrs := append([]Address{}, tb.expectedBootstrapSigners...)
+	inputs := append(tb.inputs(), tb.tx.Body.Collateral...)
+	for _, input := range inputs {
+		if input.Address != nil && input.Address.Type == Byron {
+			addrs = append(addrs, *input.Address)
+		}
+	}
+
+	missing := []Address{}
+	for _, addr := range addrs {
+		if addr.Byron == nil || containsAddress(missing, addr) {
+			continue
+		}
+		signed := false
+		for _, xkey := range tb.xkeys {
+			if addr.IsByronKey(xkey.XPubKey()) {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			missing = append(missing, addr)
+		}
+	}
+	return missing
+}
 
+func containsAddress(addrs []Address, addr Address) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a.Bytes(), addr.Bytes()) {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrapAddress returns the address of the first Byron input derived from
+// the given extended public key, or nil if there is none.
+func (tb *TxBuilder) bootstrapAddress(xpub crypto.XPubKey) *Address {
+	inputs := append(tb.inputs(), tb.tx.Body.Collateral...)
+	for _, input := range inputs {
+		if input.Address != nil && input.Address.IsByronKey(xpub) {
+			return input.Address
+		}
+	}
 	return nil
 }
 
@@ -288,5 +1029,128 @@ func (tb *TxBuilder) buildBody() error {
 		auxHash32 := Hash32(auxHash[:])
 		tb.tx.Body.AuxiliaryDataHash = &auxHash32
 	}
+	if len(tb.tx.WitnessSet.Redeemers) > 0 || len(tb.tx.WitnessSet.PlutusData) > 0 {
+		languageViews, err := tb.protocol.CostModels.LanguageViews(tb.languages())
+		if err != nil {
+			return err
+		}
+		hash, err := scriptDataHash(&tb.tx.WitnessSet, languageViews, tb.protocol.IsConway())
+		if err != nil {
+			return err
+		}
+		tb.tx.Body.ScriptDataHash = &hash
+	}
+	return tb.buildCollateral()
+}
+
+// languages returns the Plutus languages used by the transaction, including the ones
+// used by the reference scripts of the inputs and reference inputs.
+func (tb *TxBuilder) languages() []Language {
+	used := map[Language]bool{}
+	for _, lang := range tb.tx.WitnessSet.Languages() {
+		used[lang] = true
+	}
+	for _, inputs := range [][]TxInput{tb.inputs(), tb.tx.Body.ReferenceInputs} {
+		for _, input := range inputs {
+			if input.ScriptRef == nil {
+				continue
+			}
+			switch input.ScriptRef.Type {
+			case PlutusV1ScriptRef:
+				used[PlutusV1] = true
+			case PlutusV2ScriptRef:
+				used[PlutusV2] = true
+			case PlutusV3ScriptRef:
+				used[PlutusV3] = true
+			}
+		}
+	}
+
+	languages := []Language{}
+	for _, lang := range []Language{PlutusV1, PlutusV2, PlutusV3} {
+		if used[lang] {
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
+func (tb *TxBuilder) inputs() []TxInput {
+	inputs := make([]TxInput, len(tb.tx.Body.Inputs))
+	for i, input := range tb.tx.Body.Inputs {
+		inputs[i] = *input
+	}
+	return inputs
+}
+
+// requiredCollateral computes the minimal collateral required for the transaction fee.
+func (tb *TxBuilder) requiredCollateral() Coin {
+	percentage := Coin(tb.protocol.CollateralPercentage)
+	return (tb.tx.Body.Fee*percentage + 99) / 100
+}
+
+// buildCollateral validates the collateral inputs and computes the total collateral
+// and the collateral return output if a collateral return address was provided.
+func (tb *TxBuilder) buildCollateral() error {
+	tb.tx.Body.CollateralReturn = nil
+	tb.tx.Body.TotalCollateral = 0
+
+	collateral := tb.tx.Body.Collateral
+	if len(collateral) == 0 {
+		return nil
+	}
+	if max := tb.protocol.MaxCollateralInputs; max != 0 && uint(len(collateral)) > max {
+		return fmt.Errorf("too many collateral inputs, got %v want at most %v", len(collateral), max)
+	}
+
+	collateralAmount := NewValue(0)
+	for _, input := range collateral {
+		if input.Amount == nil {
+			if tb.collateralReceiver != nil {
+				return fmt.Errorf("missing amount for collateral input %v", input)
+			}
+			// Without amounts the collateral can't be validated
+			return nil
+		}
+		collateralAmount = collateralAmount.Add(input.Amount)
+	}
+
+	required := tb.requiredCollateral()
+	if collateralAmount.Coin < required {
+		return fmt.Errorf(
+			"insuficient collateral in transaction, got %v want atleast %v",
+			collateralAmount.Coin,
+			required,
+		)
+	}
+
+	if tb.collateralReceiver == nil {
+		if !collateralAmount.OnlyCoin() {
+			return fmt.Errorf("collateral inputs with multiassets require a collateral return output")
+		}
+		return nil
+	}
+
+	if tb.protocol.CollateralPercentage == 0 {
+		return fmt.Errorf("collateral percentage is required to compute the collateral return")
+	}
+
+	returnAmount := collateralAmount.Sub(NewValue(required))
+	returnOutput := NewTxOutput(*tb.collateralReceiver, returnAmount)
+	if returnMinCoins := tb.MinCoinsForTxOut(returnOutput); returnAmount.Coin < returnMinCoins {
+		if returnAmount.OnlyCoin() {
+			// The excess is too small for a collateral return output
+			return nil
+		}
+		return fmt.Errorf(
+			"insuficient collateral for collateral return with multiassets, got %v want %v",
+			collateralAmount.Coin,
+			required+returnMinCoins,
+		)
+	}
+
+	tb.tx.Body.CollateralReturn = returnOutput
+	tb.tx.Body.TotalCollateral = required
+
 	return nil
 }
@@ -1,6 +1,8 @@
 package cardano
 
 import (
+	"encoding/hex"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -13,6 +15,13 @@ var alonzoProtocol = &ProtocolParams{
 	MinFeeB:          155381,
 }
 
+var babbageProtocol = &ProtocolParams{
+	CoinsPerUTxOByte: 4310,
+	MinFeeA:          44,
+	MinFeeB:          155381,
+	ProtocolVersion:  ProtocolVersion{Major: 8},
+}
+
 // See https://github.com/input-output-hk/cardano-ledger/blob/master/doc/explanations/min-utxo-alonzo.rst
 func TestMinUTXO(t *testing.T) {
 	pubKeys := []crypto.PubKey{
@@ -99,6 +108,70 @@ func TestMinUTXO(t *testing.T) {
 	}
 }
 
+func TestBabbageMinUTXO(t *testing.T) {
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	cred, err := NewKeyCredential(key.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	enterpriseAddr, err := NewEnterpriseAddress(Testnet, cred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseAddr, err := NewBaseAddress(Testnet, cred, cred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := NewBytesData(make([]byte, 100))
+
+	testcases := []struct {
+		name     string
+		protocol *ProtocolParams
+		output   *TxOutput
+		minUTXO  Coin
+	}{
+		{
+			name:     "Enterprise address",
+			protocol: babbageProtocol,
+			output:   NewTxOutput(enterpriseAddr, NewValue(0)),
+			minUTXO:  Coin(849070),
+		},
+		{
+			name:     "Base address",
+			protocol: babbageProtocol,
+			output:   NewTxOutput(baseAddr, NewValue(0)),
+			minUTXO:  Coin(969750),
+		},
+		{
+			name:     "Base address with inline datum",
+			protocol: babbageProtocol,
+			output:   &TxOutput{Address: baseAddr, Amount: NewValue(0), Datum: &datum},
+			minUTXO:  Coin(1465400),
+		},
+		{
+			name:     "Without protocol version",
+			protocol: &ProtocolParams{CoinsPerUTxOByte: 4310},
+			output:   NewTxOutput(baseAddr, NewValue(0)),
+			minUTXO:  Coin(969750),
+		},
+		{
+			name:     "Alonzo protocol version",
+			protocol: &ProtocolParams{CoinsPerUTXOWord: 34482, CoinsPerUTxOByte: 4310, ProtocolVersion: ProtocolVersion{Major: 6}},
+			output:   NewTxOutput(baseAddr, NewValue(0)),
+			minUTXO:  Coin(965496),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(tc.protocol)
+			if got, want := txBuilder.MinCoinsForTxOut(tc.output), tc.minUTXO; got != want {
+				t.Errorf("invalid minUTXO\ngot: %d\nwant: %d", got, want)
+			}
+		})
+	}
+}
+
 func TestSimpleTx(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -593,3 +666,749 @@ func TestAddChangeIfNeeded(t *testing.T) {
 		})
 	}
 }
+
+func TestScriptDataHashBuild(t *testing.T) {
+	script, err := hex.DecodeString("4d01000033222220051200120011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	redeemer := Redeemer{
+		Tag:     RedeemerTagSpend,
+		Data:    NewIntData(42),
+		ExUnits: ExUnits{Mem: 100, Steps: 200},
+	}
+
+	testcases := []struct {
+		name       string
+		costModels CostModels
+		wantErr    bool
+	}{
+		{
+			name:       "ok",
+			costModels: CostModels{PlutusV1: {1, 2}},
+		},
+		{
+			name:    "missing cost model",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			protocol := *alonzoProtocol
+			protocol.CostModels = tc.costModels
+
+			txBuilder := NewTxBuilder(&protocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(10e6)))
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(5e6)))
+			txBuilder.AddPlutusV1Script(script)
+			txBuilder.AddRedeemer(redeemer)
+			txBuilder.AddDatum(NewIntData(1))
+			txBuilder.AddChangeIfNeeded(addr)
+
+			tx, err := txBuilder.Build()
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected error")
+			}
+
+			views, err := tc.costModels.LanguageViews([]Language{PlutusV1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ws := &WitnessSet{Redeemers: []Redeemer{redeemer}, PlutusData: []PlutusData{NewIntData(1)}}
+			want, err := scriptDataHash(ws, views, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tx.Body.ScriptDataHash == nil {
+				t.Fatal("missing script data hash")
+			}
+			if got := *tx.Body.ScriptDataHash; got.String() != want.String() {
+				t.Errorf("invalid script data hash\ngot: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestCollateralReturn(t *testing.T) {
+	babbageProtocol := *alonzoProtocol
+	babbageProtocol.CollateralPercentage = 150
+	babbageProtocol.MaxCollateralInputs = 2
+
+	key := crypto.NewXPrvKeyFromEntropy([]byte("collateral"), "")
+	policyKey := crypto.NewXPrvKeyFromEntropy([]byte("policy"), "")
+	policyScript, err := NewScriptPubKey(policyKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID, err := NewPolicyID(policyScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets := NewMultiAsset().Set(policyID, NewAssets().Set(NewAssetName("cardanogo"), 10))
+
+	testcases := []struct {
+		name       string
+		collateral []*Value
+		returnAddr bool
+		hasReturn  bool
+		wantErr    bool
+	}{
+		{
+			name:       "collateral return",
+			collateral: []*Value{NewValue(10e6)},
+			returnAddr: true,
+			hasReturn:  true,
+		},
+		{
+			name:       "collateral return with multiassets",
+			collateral: []*Value{NewValueWithAssets(10e6, assets)},
+			returnAddr: true,
+			hasReturn:  true,
+		},
+		{
+			name:       "collateral without return",
+			collateral: []*Value{NewValue(10e6)},
+		},
+		{
+			name:       "multiassets without return",
+			collateral: []*Value{NewValueWithAssets(10e6, assets)},
+			wantErr:    true,
+		},
+		{
+			name:       "insuficient collateral",
+			collateral: []*Value{NewValue(1e5)},
+			returnAddr: true,
+			wantErr:    true,
+		},
+		{
+			name:       "too many collateral inputs",
+			collateral: []*Value{NewValue(1e6), NewValue(1e6), NewValue(1e6)},
+			returnAddr: true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(&babbageProtocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(20e6)))
+			txBuilder.AddReferenceInputs(NewTxInput(txHash, 1, nil))
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(5e6)))
+			for i, amount := range tc.collateral {
+				txBuilder.AddCollateralInputs(NewTxInput(txHash, uint(i+2), amount))
+			}
+			if tc.returnAddr {
+				txBuilder.AddCollateralReturnIfNeeded(addr)
+			}
+			txBuilder.Sign(key.PrvKey())
+			txBuilder.AddChangeIfNeeded(addr)
+
+			tx, err := txBuilder.Build()
+			if err != nil {
+				if tc.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				t.Fatal("expected error")
+			}
+
+			if !tc.hasReturn {
+				if tx.Body.CollateralReturn != nil || tx.Body.TotalCollateral != 0 {
+					t.Fatalf("unexpected collateral return %v", tx.Body.CollateralReturn)
+				}
+				return
+			}
+
+			wantTotal := (tx.Body.Fee*150 + 99) / 100
+			if got := tx.Body.TotalCollateral; got != wantTotal {
+				t.Errorf("invalid total collateral: got %v want %v", got, wantTotal)
+			}
+			wantReturn := tc.collateral[0].Sub(NewValue(wantTotal))
+			if got := tx.Body.CollateralReturn.Amount; got.Cmp(wantReturn) != 0 {
+				t.Errorf("invalid collateral return: got %v want %v", got, wantReturn)
+			}
+
+			gotTx := &Tx{}
+			if err := gotTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := gotTx.Body.TotalCollateral, tx.Body.TotalCollateral; got != want {
+				t.Errorf("invalid total collateral encoding: got %v want %v", got, want)
+			}
+			if got, want := gotTx.Body.CollateralReturn.Amount, tx.Body.CollateralReturn.Amount; got.Cmp(want) != 0 {
+				t.Errorf("invalid collateral return encoding: got %v want %v", got, want)
+			}
+			if got, want := len(gotTx.Body.ReferenceInputs), 1; got != want {
+				t.Errorf("invalid reference inputs encoding: got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWithdrawals(t *testing.T) {
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	stakeKey := crypto.NewXPrvKeyFromEntropy([]byte("stake"), "")
+	stakeCred, err := NewKeyCredential(stakeKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := Address{Network: Testnet, Type: Reward, Stake: stakeCred}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputAmount, withdrawalAmount := Coin(5e6), Coin(3e6)
+
+	txBuilder := NewTxBuilder(alonzoProtocol)
+	txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(inputAmount)))
+	txBuilder.AddWithdrawal(rewardAddr, withdrawalAmount)
+	txBuilder.Sign(key.PrvKey())
+	txBuilder.Sign(stakeKey.PrvKey())
+	txBuilder.AddChangeIfNeeded(addr)
+
+	tx, err := txBuilder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tx.Body.Outputs[0].Amount.Coin+tx.Body.Fee, inputAmount+withdrawalAmount; got != want {
+		t.Errorf("invalid change+fee: got %v want %v", got, want)
+	}
+
+	minFee, err := txBuilder.MinFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tx.Body.Fee, minFee; got != want {
+		t.Errorf("invalid tx fee:\ngot: %v\nwant: %v", got, want)
+	}
+
+	gotTx := &Tx{}
+	if err := gotTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotTx.Body.Withdrawals.Get(rewardAddr), withdrawalAmount; got != want {
+		t.Errorf("invalid withdrawal encoding: got %v want %v", got, want)
+	}
+}
+
+func TestBootstrapWitness(t *testing.T) {
+	byronKey := crypto.NewXPrvKeyFromEntropy([]byte("byron"), "")
+	shelleyKey := crypto.NewXPrvKeyFromEntropy([]byte("shelley"), "")
+	magic := uint32(1)
+	byronAddr, err := NewByronAddress(byronKey.XPubKey(), ByronAddressAttributes{NetworkMagic: &magic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byronInput := NewTxInput(txHash, 0, NewValue(5e6))
+	byronInput.Address = &byronAddr
+
+	txBuilder := NewTxBuilder(alonzoProtocol)
+	txBuilder.AddInputs(byronInput, NewTxInput(txHash, 1, NewValue(2e6)))
+	txBuilder.SignWithXPrvKeys(byronKey, shelleyKey)
+	txBuilder.AddChangeIfNeeded(addr)
+
+	tx, err := txBuilder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(tx.WitnessSet.BootstrapWitnesses), 1; got != want {
+		t.Fatalf("invalid number of bootstrap witnesses\ngot: %v\nwant: %v", got, want)
+	}
+	if got, want := len(tx.WitnessSet.VKeyWitnessSet), 1; got != want {
+		t.Fatalf("invalid number of vkey witnesses\ngot: %v\nwant: %v", got, want)
+	}
+
+	gotTx := &Tx{}
+	if err := gotTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	witness := gotTx.WitnessSet.BootstrapWitnesses[0]
+	txHashBytes, err := gotTx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !witness.VKey.Verify(txHashBytes, witness.Signature) {
+		t.Errorf("invalid bootstrap witness signature")
+	}
+
+	// The address root must be derivable from the witness
+	xpub := crypto.XPubKey(append(append([]byte{}, witness.VKey...), witness.ChainCode...))
+	root, err := byronAddressRoot(xpub, witness.Attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := root.String(), byronAddr.Byron.Root.String(); got != want {
+		t.Errorf("invalid bootstrap witness root\ngot: %v\nwant: %v", got, want)
+	}
+
+	minFee, err := txBuilder.MinFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tx.Body.Fee, minFee; got != want {
+		t.Errorf("invalid tx fee:\ngot: %v\nwant: %v", got, want)
+	}
+}
+
+func TestNativeScriptBuild(t *testing.T) {
+	keyA := crypto.NewXPrvKeyFromEntropy([]byte("keyA"), "")
+	keyB := crypto.NewXPrvKeyFromEntropy([]byte("keyB"), "")
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigA, err := NewScriptPubKey(keyA.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigB, err := NewScriptPubKey(keyB.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := NativeScript{Type: ScriptAll, Scripts: []NativeScript{
+		sigA,
+		sigB,
+		{Type: ScriptInvalidBefore, IntervalValue: 1000},
+		{Type: ScriptInvalidAfter, IntervalValue: 5000},
+	}}
+
+	build := func(keys ...crypto.XPrvKey) *Tx {
+		txBuilder := NewTxBuilder(alonzoProtocol)
+		txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(10e6)))
+		txBuilder.AddOutputs(NewTxOutput(addr, NewValue(2e6)))
+		txBuilder.AddNativeScript(script)
+		txBuilder.SetTTL(8000)
+		for _, key := range keys {
+			txBuilder.Sign(key.PrvKey())
+		}
+		txBuilder.AddChangeIfNeeded(addr)
+		tx, err := txBuilder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// keyB signs the transaction later
+	partialTx := build(keyA)
+	if got, want := *partialTx.Body.ValidityIntervalStart, uint64(1000); got != want {
+		t.Errorf("invalid validity interval start\ngot: %v\nwant: %v", got, want)
+	}
+	if got, want := *partialTx.Body.TTL, uint64(5000); got != want {
+		t.Errorf("invalid ttl\ngot: %v\nwant: %v", got, want)
+	}
+
+	fullTx := build(keyA, keyB)
+	if got, want := partialTx.Body.Fee, fullTx.Body.Fee; got != want {
+		t.Errorf("invalid fee estimation\ngot: %v\nwant: %v", got, want)
+	}
+
+	signers := []AddrKeyHash{sigA.KeyHash, sigB.KeyHash}
+	if !script.Evaluate(signers, fullTx.Body.ValidityIntervalStart, fullTx.Body.TTL) {
+		t.Errorf("native script not satisfied by the transaction")
+	}
+}
+
+func TestNativeScriptKeepsTTL(t *testing.T) {
+	keyA := crypto.NewXPrvKeyFromEntropy([]byte("keyA"), "")
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigA, err := NewScriptPubKey(keyA.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := NativeScript{Type: ScriptAny, Scripts: []NativeScript{
+		sigA,
+		{Type: ScriptInvalidAfter, IntervalValue: 100},
+	}}
+
+	testcases := []struct {
+		name     string
+		keys     []crypto.XPrvKey
+		expected []AddrKeyHash
+	}{
+		{name: "signed by key A", keys: []crypto.XPrvKey{keyA}},
+		{name: "key A expected", expected: []AddrKeyHash{sigA.KeyHash}},
+		{name: "key A signs later"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(alonzoProtocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(10e6)))
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(2e6)))
+			txBuilder.AddNativeScript(script)
+			txBuilder.SetTTL(1000)
+			for _, key := range tc.keys {
+				txBuilder.Sign(key.PrvKey())
+			}
+			txBuilder.AddExpectedSigners(tc.expected...)
+			txBuilder.AddChangeIfNeeded(addr)
+			tx, err := txBuilder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := *tx.Body.TTL, uint64(1000); got != want {
+				t.Errorf("invalid ttl\ngot: %v\nwant: %v", got, want)
+			}
+			if tx.Body.ValidityIntervalStart != nil {
+				t.Errorf("invalid validity interval start\ngot: %v\nwant: %v", *tx.Body.ValidityIntervalStart, nil)
+			}
+		})
+	}
+}
+
+func TestNativeScriptExpectedSignersFee(t *testing.T) {
+	keyA := crypto.NewXPrvKeyFromEntropy([]byte("keyA"), "")
+	keyB := crypto.NewXPrvKeyFromEntropy([]byte("keyB"), "")
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigA, err := NewScriptPubKey(keyA.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigB, err := NewScriptPubKey(keyB.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := NativeScript{Type: ScriptAny, Scripts: []NativeScript{sigA, sigB}}
+
+	build := func(sign bool) *Tx {
+		txBuilder := NewTxBuilder(alonzoProtocol)
+		txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(10e6)))
+		txBuilder.AddOutputs(NewTxOutput(addr, NewValue(2e6)))
+		txBuilder.AddNativeScript(script)
+		if sign {
+			txBuilder.Sign(keyB.PrvKey())
+		} else {
+			txBuilder.AddExpectedSigners(sigB.KeyHash)
+		}
+		txBuilder.AddChangeIfNeeded(addr)
+		tx, err := txBuilder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// keyB is declared and signs the transaction later
+	unsignedTx := build(false)
+	signedTx := build(true)
+	if got, want := len(unsignedTx.WitnessSet.VKeyWitnessSet), 0; got != want {
+		t.Errorf("invalid number of witnesses\ngot: %v\nwant: %v", got, want)
+	}
+	if got, want := unsignedTx.Body.Fee, signedTx.Body.Fee; got != want {
+		t.Errorf("invalid fee estimation\ngot: %v\nwant: %v", got, want)
+	}
+	minFee := alonzoProtocol.MinFeeA*Coin(len(signedTx.Bytes())) + alonzoProtocol.MinFeeB
+	if got, want := unsignedTx.Body.Fee, minFee; got != want {
+		t.Errorf("invalid fee\ngot: %v\nwant: %v", got, want)
+	}
+}
+
+func TestDummyWitnessesFee(t *testing.T) {
+	byronKey := crypto.NewXPrvKeyFromEntropy([]byte("byron"), "")
+	paymentKey := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	requiredKey := crypto.NewXPrvKeyFromEntropy([]byte("required"), "")
+	remoteKey := crypto.NewXPrvKeyFromEntropy([]byte("remote"), "")
+
+	magic := uint32(1)
+	byronAddr, err := NewByronAddress(byronKey.XPubKey(), ByronAddressAttributes{NetworkMagic: &magic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := NewKeyCredential(paymentKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentAddr, err := NewEnterpriseAddress(Testnet, payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requiredKeyHash, err := requiredKey.PubKey().Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteKeyHash, err := remoteKey.PubKey().Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(sign bool) (*Tx, Coin) {
+		byronInput := NewTxInput(txHash, 0, NewValue(5e6))
+		byronInput.Address = &byronAddr
+		paymentInput := NewTxInput(txHash, 1, NewValue(5e6))
+		paymentInput.Address = &paymentAddr
+
+		txBuilder := NewTxBuilder(alonzoProtocol)
+		txBuilder.AddInputs(byronInput, paymentInput)
+		txBuilder.AddOutputs(NewTxOutput(paymentAddr, NewValue(2e6)))
+		txBuilder.AddRequiredSigners(requiredKeyHash)
+		if sign {
+			txBuilder.SignWithXPrvKeys(byronKey)
+			txBuilder.Sign(paymentKey.PrvKey(), requiredKey.PrvKey(), remoteKey.PrvKey())
+		} else {
+			txBuilder.AddExpectedSigners(remoteKeyHash)
+		}
+		minFee, err := txBuilder.MinFee()
+		if err != nil {
+			t.Fatal(err)
+		}
+		txBuilder.AddChangeIfNeeded(paymentAddr)
+		tx, err := txBuilder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx, minFee
+	}
+
+	unsignedTx, unsignedMinFee := build(false)
+	signedTx, signedMinFee := build(true)
+	if got, want := len(unsignedTx.WitnessSet.VKeyWitnessSet)+len(unsignedTx.WitnessSet.BootstrapWitnesses), 0; got != want {
+		t.Errorf("invalid number of witnesses\ngot: %v\nwant: %v", got, want)
+	}
+	if got, want := unsignedTx.Body.Fee, signedTx.Body.Fee; got != want {
+		t.Errorf("invalid fee estimation\ngot: %v\nwant: %v", got, want)
+	}
+	if got, want := unsignedMinFee, signedMinFee; got != want {
+		t.Errorf("invalid min fee estimation\ngot: %v\nwant: %v", got, want)
+	}
+}
+
+func TestScriptFees(t *testing.T) {
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocol := &ProtocolParams{
+		CoinsPerUTxOByte:           4310,
+		MinFeeA:                    44,
+		MinFeeB:                    155381,
+		ProtocolVersion:            ProtocolVersion{Major: 9},
+		ExecutionCosts:             ExUnitPrices{Mem: Rational{P: 577, Q: 10000}, Steps: Rational{P: 721, Q: 10000000}},
+		MinFeeRefScriptCostPerByte: Rational{P: 15, Q: 1},
+	}
+
+	testcases := []struct {
+		name          string
+		exUnits       []ExUnits
+		refScripts    []int // sizes of the reference scripts of the inputs
+		wantScriptFee Coin
+		wantRefFee    Coin
+	}{
+		{name: "No scripts"},
+		{
+			name:          "Execution units",
+			exUnits:       []ExUnits{{Mem: 600000, Steps: 200000000}, {Mem: 400000, Steps: 300000000}},
+			wantScriptFee: 93750,
+		},
+		{
+			name:          "Execution units rounded up",
+			exUnits:       []ExUnits{{Mem: 1, Steps: 1}},
+			wantScriptFee: 1,
+		},
+		{
+			name:       "Reference scripts in one tier",
+			refScripts: []int{1000, 2000},
+			wantRefFee: 45000,
+		},
+		{
+			name:       "Reference scripts in two tiers",
+			refScripts: []int{30000},
+			wantRefFee: 463200,
+		},
+		{
+			name:       "Reference scripts in three tiers",
+			refScripts: []int{20000, 40000},
+			wantRefFee: 1034880,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBuilder := NewTxBuilder(protocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, NewValue(100e6)))
+			for i, size := range tc.refScripts {
+				input := NewTxInput(txHash, uint(i+1), NewValue(0))
+				input.ScriptRef = NewPlutusV2ScriptRef(make([]byte, size))
+				txBuilder.AddReferenceInputs(input)
+			}
+			for i, exUnits := range tc.exUnits {
+				txBuilder.AddRedeemer(Redeemer{Tag: RedeemerTagMint, Index: uint64(i), Data: NewIntegerData(big.NewInt(0)), ExUnits: exUnits})
+			}
+			txBuilder.AddOutputs(NewTxOutput(addr, NewValue(2e6)))
+			txBuilder.AddChangeIfNeeded(addr)
+
+			tx, err := txBuilder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := txBuilder.scriptsFee(); got != tc.wantScriptFee {
+				t.Errorf("invalid script fee\ngot: %v\nwant: %v", got, tc.wantScriptFee)
+			}
+			if got := txBuilder.refScriptsFee(); got != tc.wantRefFee {
+				t.Errorf("invalid reference scripts fee\ngot: %v\nwant: %v", got, tc.wantRefFee)
+			}
+			sizeFee := protocol.MinFeeA*Coin(len(tx.Bytes())) + protocol.MinFeeB
+			if got, want := tx.Body.Fee, sizeFee+tc.wantScriptFee+tc.wantRefFee; got != want {
+				t.Errorf("invalid fee\ngot: %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestChangeSplitting(t *testing.T) {
+	policyKey := crypto.NewXPrvKeyFromEntropy([]byte("policy"), "")
+	policyScript, err := NewScriptPubKey(policyKey.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID, err := NewPolicyID(policyScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assets := NewAssets()
+	for i := 0; i < 30; i++ {
+		assets.Set(NewAssetName(fmt.Sprintf("cardanogo%02d", i)), BigNum(i+1))
+	}
+	inputAmount := NewValueWithAssets(100e6, NewMultiAsset().Set(policyID, assets))
+
+	protocol := *babbageProtocol
+	protocol.MaxValueSize = 200
+	protocol.MaxTxSize = 16384
+
+	txBuilder := NewTxBuilder(&protocol)
+	txBuilder.AddInputs(NewTxInput(txHash, 0, inputAmount))
+	txBuilder.AddOutputs(NewTxOutput(addr, NewValue(2e6)))
+	txBuilder.AddChangeIfNeeded(addr)
+	tx, err := txBuilder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The last output is the payment, the others are the change
+	changeOutputs := tx.Body.Outputs[:len(tx.Body.Outputs)-1]
+	if len(changeOutputs) < 2 {
+		t.Fatalf("change output not split\ngot: %v outputs", len(changeOutputs))
+	}
+	outputAmount := NewValue(tx.Body.Fee)
+	for i, output := range tx.Body.Outputs {
+		if size := valueSize(output.Amount); size > protocol.MaxValueSize {
+			t.Errorf("output %d value too large\ngot: %v\nwant: <= %v", i, size, protocol.MaxValueSize)
+		}
+		if minCoins := txBuilder.MinCoinsForTxOut(output); output.Amount.Coin < minCoins {
+			t.Errorf("output %d without min coins\ngot: %v\nwant: >= %v", i, output.Amount.Coin, minCoins)
+		}
+		outputAmount = outputAmount.Add(output.Amount)
+	}
+	if inputAmount.Cmp(outputAmount) != 0 {
+		t.Errorf("unbalanced transaction\ngot: %v\nwant: %v", outputAmount, inputAmount)
+	}
+	minFee, err := txBuilder.MinFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tx.Body.Fee, minFee; got != want {
+		t.Errorf("invalid fee\ngot: %v\nwant: %v", got, want)
+	}
+
+	// Outputs exceeding the limits are rejected
+	testcases := []struct {
+		name         string
+		maxValueSize uint
+		maxTxSize    uint
+	}{
+		{name: "Value size", maxValueSize: 200},
+		{name: "Transaction size", maxTxSize: 300},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			protocol := *babbageProtocol
+			protocol.MaxValueSize = tc.maxValueSize
+			protocol.MaxTxSize = tc.maxTxSize
+
+			txBuilder := NewTxBuilder(&protocol)
+			txBuilder.AddInputs(NewTxInput(txHash, 0, inputAmount))
+			txBuilder.AddOutputs(NewTxOutput(addr, inputAmount.Sub(NewValue(98e6))))
+			txBuilder.AddChangeIfNeeded(addr)
+			if _, err := txBuilder.Build(); err == nil {
+				t.Error("expected size limit error")
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/echovl/cardano-go/crypto"
 	"github.com/echovl/cardano-go/internal/cbor"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestTxEncoding(t *testing.T) {
@@ -364,3 +365,279 @@ func TestRelayEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestWitnessSetEncoding(t *testing.T) {
+	script, err := hex.DecodeString("4d01000033222220051200120011")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := WitnessSet{
+		PlutusV1Scripts: []PlutusV1Script{script},
+		PlutusV2Scripts: []PlutusV2Script{script},
+		PlutusV3Scripts: []PlutusV3Script{script},
+	}
+
+	data, err := cborEnc.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantHex := "a303814e4d0100003322222005120012001106814e4d0100003322222005120012001107814e4d01000033222220051200120011"
+	if got := hex.EncodeToString(data); got != wantHex {
+		t.Errorf("invalid witness set encoding\ngot: %s\nwant: %s", got, wantHex)
+	}
+
+	var got WitnessSet
+	if err := cborDec.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestTxOutputEncoding(t *testing.T) {
+	addr, err := NewAddress("addr_test1vp9uhllavnhwc6m6422szvrtq3eerhleer4eyu00rmx8u6c42z3v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	datumHash, err := NewHash32("923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := hex.DecodeString("4d01000033222220051200120011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := NewConstrData(0)
+
+	addrHex := "581d604bcbfffd64eeec6b7aaa9501306b047391dff9c8eb9271ef1ecc7e6b"
+	amountHex := "1a000f4240"
+
+	testcases := []struct {
+		name       string
+		cborHex    string
+		output     TxOutput
+		postAlonzo bool
+	}{
+		{
+			name:    "legacy",
+			cborHex: "82" + addrHex + amountHex,
+			output:  TxOutput{Address: addr, Amount: NewValue(1e6)},
+		},
+		{
+			name:    "legacy with datum hash",
+			cborHex: "83" + addrHex + amountHex + "5820" + datumHash.String(),
+			output:  TxOutput{Address: addr, Amount: NewValue(1e6), DatumHash: datumHash},
+		},
+		{
+			name:       "post-alonzo",
+			cborHex:    "a200" + addrHex + "01" + amountHex,
+			output:     TxOutput{Address: addr, Amount: NewValue(1e6), postAlonzo: true},
+			postAlonzo: true,
+		},
+		{
+			name:       "post-alonzo with datum hash",
+			cborHex:    "a300" + addrHex + "01" + amountHex + "0282005820" + datumHash.String(),
+			output:     TxOutput{Address: addr, Amount: NewValue(1e6), DatumHash: datumHash, postAlonzo: true},
+			postAlonzo: true,
+		},
+		{
+			name:       "post-alonzo with inline datum",
+			cborHex:    "a300" + addrHex + "01" + amountHex + "028201d81843d87980",
+			output:     TxOutput{Address: addr, Amount: NewValue(1e6), Datum: &unit, postAlonzo: true},
+			postAlonzo: true,
+		},
+		{
+			name:    "post-alonzo with plutus script ref",
+			cborHex: "a300" + addrHex + "01" + amountHex + "03d8185182024e4d01000033222220051200120011",
+			output: TxOutput{
+				Address:    addr,
+				Amount:     NewValue(1e6),
+				ScriptRef:  NewPlutusV2ScriptRef(script),
+				postAlonzo: true,
+			},
+			postAlonzo: true,
+		},
+		{
+			name: "post-alonzo with native script ref",
+			cborHex: "a300" + addrHex + "01" + amountHex +
+				"03d818582282008200581c4bcbfffd64eeec6b7aaa9501306b047391dff9c8eb9271ef1ecc7e6b",
+			output: TxOutput{
+				Address: addr,
+				Amount:  NewValue(1e6),
+				ScriptRef: NewNativeScriptRef(NativeScript{
+					Type:    ScriptPubKey,
+					KeyHash: addr.Payment.KeyHash,
+				}),
+				postAlonzo: true,
+			},
+			postAlonzo: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.cborHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out TxOutput
+			if err := cborDec.Unmarshal(data, &out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(out, tc.output) {
+				t.Errorf("got: %+v\nwant: %+v", out, tc.output)
+			}
+			if got, want := out.IsPostAlonzo(), tc.postAlonzo; got != want {
+				t.Errorf("invalid output format: got %v want %v", got, want)
+			}
+
+			rb, err := cborEnc.Marshal(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(rb); got != tc.cborHex {
+				t.Errorf("got: %s\nwant: %s", got, tc.cborHex)
+			}
+		})
+	}
+}
+
+func TestTxOriginalEncoding(t *testing.T) {
+	// Body with outputs before inputs and an indefinite-length inputs array
+	bodyHex := "a3" +
+		"018182581d604bcbfffd64eeec6b7aaa9501306b047391dff9c8eb9271ef1ecc7e6b1a000f4240" +
+		"009f825820030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f351800ff" +
+		"021a0002a0d5"
+	txHex := "84" + bodyHex + "a0f5f6"
+
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyBytes, _ := hex.DecodeString(bodyHex)
+
+	tx := &Tx{}
+	if err := tx.UnmarshalCBOR(txBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tx.Hex(), txHex; got != want {
+		t.Errorf("invalid tx encoding\ngot: %s\nwant: %s", got, want)
+	}
+
+	wantHash := blake2b.Sum256(bodyBytes)
+	txHash, err := tx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := txHash.String(), hex.EncodeToString(wantHash[:]); got != want {
+		t.Errorf("invalid tx hash\ngot: %s\nwant: %s", got, want)
+	}
+
+	// Adding witnesses must not change the body
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	tx.WitnessSet.VKeyWitnessSet = append(tx.WitnessSet.VKeyWitnessSet, VKeyWitness{
+		VKey:      key.PubKey(),
+		Signature: key.Sign(txHash),
+	})
+	gotTx := &Tx{}
+	if err := gotTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	gotHash, err := gotTx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotHash.String(), txHash.String(); got != want {
+		t.Errorf("invalid signed tx hash\ngot: %s\nwant: %s", got, want)
+	}
+
+	// Modified bodies are re-encoded
+	gotTx.Body.Fee = 200000
+	body, err := gotTx.Body.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(body[:2]), "a300"; got != want {
+		t.Errorf("invalid modified body encoding\ngot: %s\nwant: %s", got, want)
+	}
+}
+
+func TestWitnessSetOriginalFields(t *testing.T) {
+	bodyHex := "a3" +
+		"0081825820030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f351800" +
+		"018182581d604bcbfffd64eeec6b7aaa9501306b047391dff9c8eb9271ef1ecc7e6b1a000f4240" +
+		"021a0002a0d5"
+	// Indefinite-length datums array and indefinite-length map of redeemers
+	datumsHex := "9f01ff"
+	redeemersHex := "bf82000082182a82186418c8ff"
+	txHex := "84" + bodyHex + "a2" + "04" + datumsHex + "05" + redeemersHex + "f5f6"
+
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preimage, err := hex.DecodeString(redeemersHex + datumsHex + "a0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHash := blake2b.Sum256(preimage)
+
+	tx := &Tx{}
+	if err := tx.UnmarshalCBOR(txBytes); err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := tx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := crypto.NewXPrvKeyFromEntropy([]byte("payment"), "")
+	tx.WitnessSet.VKeyWitnessSet = append(tx.WitnessSet.VKeyWitnessSet, VKeyWitness{
+		VKey:      key.PubKey(),
+		Signature: key.Sign(txHash),
+	})
+	signedTx := &Tx{}
+	if err := signedTx.UnmarshalCBOR(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(signedTx.WitnessSet.VKeyWitnessSet); got != 1 {
+		t.Fatalf("invalid number of vkey witnesses\ngot: %v\nwant: %v", got, 1)
+	}
+
+	wsBytes, err := signedTx.WitnessSet.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := getMapPairsFromCBOR(wsBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[uint64]string{}
+	for _, pair := range pairs {
+		key, err := witnessSetKey(pair[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields[key] = hex.EncodeToString(pair[1])
+	}
+	if got, want := fields[witnessSetPlutusDataKey], datumsHex; got != want {
+		t.Errorf("invalid datums encoding\ngot: %s\nwant: %s", got, want)
+	}
+	if got, want := fields[witnessSetRedeemersKey], redeemersHex; got != want {
+		t.Errorf("invalid redeemers encoding\ngot: %s\nwant: %s", got, want)
+	}
+
+	gotHash, err := scriptDataHash(&signedTx.WitnessSet, []byte{0xa0}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotHash.String(), hex.EncodeToString(wantHash[:]); got != want {
+		t.Errorf("invalid script data hash\ngot: %s\nwant: %s", got, want)
+	}
+}
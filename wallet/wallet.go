@@ -31,7 +31,8 @@ type Wallet struct {
 	network  cardano.Network
 }
 
-// Transfer sends an amount of lovelace to the receiver address and returns the transaction hash
+// Transfer sends an amount of lovelace to the receiver address and returns the transaction hash.
+// The inputs are selected using the Random-Improve coin selection.
 func (w *Wallet) Transfer(receiver cardano.Address, amount *cardano.Value) (*cardano.Hash32, error) {
 	// Calculate if the account has enough balance
 	balance, err := w.Balance()
@@ -43,70 +44,59 @@ func (w *Wallet) Transfer(receiver cardano.Address, amount *cardano.Value) (*car
 		return nil, fmt.Errorf("Not enough balance, %v > %v", amount, balance)
 	}
 
-	// Find utxos that cover the amount to transfer
-	pickedUtxos := []cardano.UTxO{}
 	utxos, err := w.findUtxos()
-	pickedUtxosAmount := cardano.NewValue(0)
-	for _, utxo := range utxos {
-		if pickedUtxosAmount.Cmp(amount) == 1 {
-			break
-		}
-		pickedUtxos = append(pickedUtxos, utxo)
-		pickedUtxosAmount = pickedUtxosAmount.Add(utxo.Amount)
-	}
-
-	pparams, err := w.node.ProtocolParams()
 	if err != nil {
 		return nil, err
 	}
-
-	txBuilder := cardano.NewTxBuilder(pparams)
-
-	keys := make(map[int]crypto.XPrvKey)
-	for i, utxo := range pickedUtxos {
-		for _, key := range w.addrKeys {
-			payment, err := cardano.NewKeyCredential(key.PubKey())
-			if err != nil {
-				return nil, err
-			}
-			addr, err := cardano.NewEnterpriseAddress(w.network, payment)
-			if err != nil {
-				return nil, err
-			}
-			if addr.Bech32() == utxo.Spender.Bech32() {
-				keys[i] = key
-			}
-		}
+	available := make([]*cardano.TxInput, len(utxos))
+	for i, utxo := range utxos {
+		spender := utxo.Spender
+		available[i] = &cardano.TxInput{TxHash: utxo.TxHash, Index: utxo.Index, Amount: utxo.Amount, Address: &spender}
 	}
 
-	if len(keys) != len(pickedUtxos) {
-		return nil, errors.New("not enough keys")
+	changeAddr, err := w.keyAddress(w.addrKeys[0])
+	if err != nil {
+		return nil, err
 	}
 
-	inputAmount := cardano.NewValue(0)
-	for _, utxo := range pickedUtxos {
-		txBuilder.AddInputs(&cardano.TxInput{TxHash: utxo.TxHash, Index: utxo.Index, Amount: utxo.Amount})
-		inputAmount = inputAmount.Add(utxo.Amount)
+	pparams, err := w.node.ProtocolParams()
+	if err != nil {
+		return nil, err
 	}
-	txBuilder.AddOutputs(&cardano.TxOutput{Address: receiver, Amount: amount})
 
 	tip, err := w.node.Tip()
 	if err != nil {
 		return nil, err
 	}
+
+	// Only the keys of the selected inputs sign the transaction
+	txBuilder := cardano.NewTxBuilder(pparams)
+	txBuilder.AddOutputs(&cardano.TxOutput{Address: receiver, Amount: amount})
 	txBuilder.SetTTL(tip.Slot + 1200)
-	for _, key := range keys {
-		txBuilder.Sign(key.PrvKey())
+	txBuilder.SelectInputs(cardano.RandomImprove{}, available...)
+	for _, key := range w.addrKeys {
+		txBuilder.SignInputs(key.PrvKey())
 	}
-	changeAddress := pickedUtxos[0].Spender
-	txBuilder.AddChangeIfNeeded(changeAddress)
+	txBuilder.AddChangeIfNeeded(changeAddr)
 	tx, err := txBuilder.Build()
 	if err != nil {
 		return nil, err
 	}
+	if len(tx.WitnessSet.VKeyWitnessSet) != len(spenders(tx.Body.Inputs)) {
+		return nil, errors.New("not enough keys")
+	}
 	return w.node.SubmitTx(tx)
 }
 
+// spenders returns the distinct addresses of the inputs.
+func spenders(inputs []*cardano.TxInput) map[string]bool {
+	addrs := make(map[string]bool)
+	for _, input := range inputs {
+		addrs[input.Address.Bech32()] = true
+	}
+	return addrs
+}
+
 // Balance returns the total lovelace amount of the wallet.
 func (w *Wallet) Balance() (*cardano.Value, error) {
 	balance := cardano.NewValue(0)
@@ -152,19 +142,24 @@ func (w *Wallet) AddAddress() (cardano.Address, error) {
 func (w *Wallet) Addresses() ([]cardano.Address, error) {
 	addresses := make([]cardano.Address, len(w.addrKeys))
 	for i, key := range w.addrKeys {
-		payment, err := cardano.NewKeyCredential(key.PubKey())
-		if err != nil {
-			return nil, err
-		}
-		enterpriseAddr, err := cardano.NewEnterpriseAddress(w.network, payment)
+		addr, err := w.keyAddress(key)
 		if err != nil {
 			return nil, err
 		}
-		addresses[i] = enterpriseAddr
+		addresses[i] = addr
 	}
 	return addresses, nil
 }
 
+// keyAddress returns the enterprise address of a payment key.
+func (w *Wallet) keyAddress(key crypto.XPrvKey) (cardano.Address, error) {
+	payment, err := cardano.NewKeyCredential(key.PubKey())
+	if err != nil {
+		return cardano.Address{}, err
+	}
+	return cardano.NewEnterpriseAddress(w.network, payment)
+}
+
 func (w *Wallet) Keys() (crypto.PrvKey, crypto.PrvKey) {
 	return w.addrKeys[0].PrvKey(), w.stakeKey.PrvKey()
 }
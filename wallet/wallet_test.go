@@ -49,7 +49,8 @@ func TestGenerateAddress(t *testing.T) {
 }
 
 type MockNode struct {
-	utxos []cardano.UTxO
+	utxos     []cardano.UTxO
+	submitted *cardano.Tx
 }
 
 func (n *MockNode) UTxOs(addr cardano.Address) ([]cardano.UTxO, error) {
@@ -61,6 +62,7 @@ func (n *MockNode) Tip() (*cardano.NodeTip, error) {
 }
 
 func (n *MockNode) SubmitTx(tx *cardano.Tx) (*cardano.Hash32, error) {
+	n.submitted = tx
 	return nil, nil
 }
 
@@ -92,6 +94,51 @@ func TestWalletBalance(t *testing.T) {
 	}
 }
 
+func TestWalletTransfer(t *testing.T) {
+	node := &MockNode{}
+	client := NewClient(&Options{Node: node})
+	w, _, err := client.CreateWallet("test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := w.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := cardano.NewHash32("030858db80bf94041b7b1c6fbc0754a9bd7113ec9025b1157a9a4e02135f3518")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, amount := range []cardano.Coin{1e6, 5e6, 20e6, 3e6} {
+		node.utxos = append(node.utxos, cardano.UTxO{
+			TxHash:  txHash,
+			Spender: addrs[0],
+			Amount:  cardano.NewValue(amount),
+			Index:   uint64(i),
+		})
+	}
+
+	amount := cardano.NewValue(4e6)
+	if _, err := w.Transfer(addrs[0], amount); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := node.submitted
+	if tx == nil {
+		t.Fatal("transaction not submitted")
+	}
+	inputAmount := cardano.NewValue(0)
+	for _, input := range tx.Body.Inputs {
+		inputAmount = inputAmount.Add(input.Amount)
+	}
+	if inputAmount.Cmp(amount) == -1 {
+		t.Errorf("inputs don't cover the amount\ngot: %v\nwant: %v", inputAmount, amount)
+	}
+	if got := len(tx.WitnessSet.VKeyWitnessSet); got != 1 {
+		t.Errorf("invalid number of witnesses\ngot: %v\nwant: %v", got, 1)
+	}
+}
+
 func bech32From(hrp string, bytes []byte) string {
 	enc, _ := bech32.EncodeFromBase256(hrp, bytes)
 	return enc